@@ -0,0 +1,54 @@
+package maze
+
+import (
+	"context"
+	"math/rand"
+)
+
+// AldousBroder uses the Aldous-Broder maze creation algorithm to create a maze
+// in a rectangular grid.  It performs a random walk, linking each cell to the
+// one it came from the first time the cell is visited.  Every possible maze is
+// equally likely, so the result has no directional bias.  If a mask splits the
+// grid into separate regions, only the region containing the randomly chosen
+// starting cell is carved
+func AldousBroder(g *Grid) {
+	AldousBroderRand(g, defaultRand)
+}
+
+// AldousBroderRand is AldousBroder using the provided random source
+func AldousBroderRand(g *Grid, r *rand.Rand) {
+	_ = aldousBroder(context.Background(), g, r)
+}
+
+// AldousBroderCtx is AldousBroder, but stops early and returns the context's
+// error if ctx is cancelled.  The passages carved before then are left in place
+func AldousBroderCtx(ctx context.Context, g *Grid) error {
+	return aldousBroder(ctx, g, defaultRand)
+}
+
+// aldousBroder implements AldousBroder using the provided random source,
+// stopping early if ctx is cancelled
+func aldousBroder(ctx context.Context, g *Grid, r *rand.Rand) error {
+	if g.Size() == 0 {
+		return nil
+	}
+	cell := g.RandomCellRand(r)
+	// The walk can never leave the region it starts in
+	size := len(region(cell))
+	visited := map[*Cell]bool{cell: true}
+	for steps := 0; len(visited) < size; steps++ {
+		if steps%contextCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		neighbors := cell.Neighbors()
+		neighbor := neighbors[r.Intn(len(neighbors))]
+		if !visited[neighbor] {
+			cell.Link(neighbor)
+			visited[neighbor] = true
+		}
+		cell = neighbor
+	}
+	return nil
+}
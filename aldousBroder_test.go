@@ -0,0 +1,52 @@
+package maze
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestAldousBroder(t *testing.T) {
+	sizes := []struct {
+		rows, columns int64
+	}{
+		{1, 1},
+		{1, 6},
+		{6, 1},
+		{8, 8},
+		{5, 12},
+	}
+	for _, s := range sizes {
+		for seed := int64(0); seed < 5; seed++ {
+			g := NewGrid(s.rows, s.columns)
+			AldousBroderRand(&g, rand.New(rand.NewSource(seed)))
+			if !IsPerfect(&g) {
+				t.Errorf("%dx%d seed %d: maze is not perfect:\n%s", s.rows, s.columns, seed, g.ToString())
+			}
+		}
+	}
+}
+
+func TestAldousBroderUniform(t *testing.T) {
+	// A 2x2 grid has four spanning trees, each missing one of the four
+	// passages, and each should be carved about equally often
+	const trials = 4000
+	r := rand.New(rand.NewSource(1))
+	counts := map[[2]int64]int{}
+	for i := 0; i < trials; i++ {
+		g := NewGrid(2, 2)
+		AldousBroderRand(&g, r)
+		ForEachAdjacentPair(&g, func(a, b *Cell) {
+			if !a.Linked(b) {
+				counts[[2]int64{g.cellID(a), g.cellID(b)}]++
+			}
+		})
+	}
+	if len(counts) != 4 {
+		t.Fatalf("carved %d different mazes, want 4: %v", len(counts), counts)
+	}
+	for pair, n := range counts {
+		if n < trials/4*8/10 || n > trials/4*12/10 {
+			t.Errorf("passage %v was left walled %d times in %d mazes, want about %d", pair, n, trials, trials/4)
+		}
+	}
+}
@@ -0,0 +1,327 @@
+package maze
+
+import (
+	"math"
+	"sort"
+)
+
+// PassageCentroid returns the average position of the cells in the grid,
+// weighting each cell by the number of passages leading out of it.  A maze with
+// evenly distributed passages has a centroid near the center of the grid.  A
+// grid without any passages reports its geometric center
+func (g *Grid) PassageCentroid() (rowMean, colMean float64) {
+	var rowSum, colSum, weight float64
+	for _, cell := range g.Cells() {
+		links := float64(cell.linkCount())
+		rowSum += links * float64(cell.Row)
+		colSum += links * float64(cell.Column)
+		weight += links
+	}
+	if weight == 0 {
+		return float64(g.Rows-1) / 2, float64(g.Columns-1) / 2
+	}
+	return rowSum / weight, colSum / weight
+}
+
+// MinGridForPathLength returns the dimensions of the smallest near-square grid
+// whose diameter can be at least pathLen steps long.  The longest possible path
+// through a maze visits every cell once, so a grid's diameter is bounded by
+// Size - 1; the grid returned therefore holds at least pathLen + 1 cells, and
+// its rows and columns differ by at most one
+func MinGridForPathLength(pathLen int64) (rows, cols int64) {
+	if pathLen < 1 {
+		return 1, 1
+	}
+	cells := pathLen + 1
+	side := int64(math.Sqrt(float64(cells)))
+	for side*side < cells {
+		side++
+	}
+	for side > 1 && (side-1)*(side-1) >= cells {
+		side--
+	}
+	if (side-1)*side >= cells {
+		return side - 1, side
+	}
+	return side, side
+}
+
+// ShortcutWalls returns up to k walls whose removal would most shorten the route
+// from start to goal.  Each wall is reported as the pair of adjacent cells it
+// separates, ordered from the largest reduction in path length to the smallest.
+// Walls whose removal would not shorten the route are never returned
+func ShortcutWalls(g *Grid, start, goal *Cell, k int) [][2]*Cell {
+	fromStart := ComputeDistances(start)
+	fromGoal := ComputeDistances(goal)
+	current := int64(math.MaxInt64)
+	if d, ok := fromStart.Get(goal); ok {
+		current = d
+	}
+
+	type shortcut struct {
+		wall   [2]*Cell
+		length int64
+	}
+	shortcuts := []shortcut{}
+	ForEachAdjacentPair(g, func(a, b *Cell) {
+		if a.Linked(b) {
+			return
+		}
+		// The new route may cross the wall in either direction
+		best := int64(math.MaxInt64)
+		for _, ends := range [][2]*Cell{{a, b}, {b, a}} {
+			toWall, ok1 := fromStart.Get(ends[0])
+			fromWall, ok2 := fromGoal.Get(ends[1])
+			if ok1 && ok2 && toWall+1+fromWall < best {
+				best = toWall + 1 + fromWall
+			}
+		}
+		if best < current {
+			shortcuts = append(shortcuts, shortcut{wall: [2]*Cell{a, b}, length: best})
+		}
+	})
+	sort.SliceStable(shortcuts, func(i, j int) bool {
+		return shortcuts[i].length < shortcuts[j].length
+	})
+
+	ret := [][2]*Cell{}
+	for i := 0; i < k && i < len(shortcuts); i++ {
+		ret = append(ret, shortcuts[i].wall)
+	}
+	return ret
+}
+
+// AllCellsConnected returns false if any cell in the grid is completely walled
+// in.  Cells without any neighbors, such as the only cell of a 1x1 grid, cannot
+// be linked and are ignored
+func (g *Grid) AllCellsConnected() bool {
+	for _, cell := range g.Cells() {
+		if len(cell.Neighbors()) > 0 && cell.linkCount() == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// WallDensity returns the total length of the standing walls, measured in cell
+// widths and including the outer border, divided by the number of cells.  This
+// allows mazes of different sizes to be compared.  An empty grid has a density
+// of 0
+func WallDensity(g *Grid) float64 {
+	if g.Size() == 0 {
+		return 0
+	}
+	return float64(g.wallCount()) / float64(g.Size())
+}
+
+// JunctionProfile counts the corners (cells with two passages which turn), tees
+// (cells with three passages), and crossroads (cells with four passages) in the
+// maze.  Straight corridors and dead ends aren't counted
+func (g *Grid) JunctionProfile() (corners, tees, crosses int) {
+	for _, cell := range g.Cells() {
+		switch cell.linkCount() {
+		case 2:
+			if !cell.straight() {
+				corners++
+			}
+		case 3:
+			tees++
+		case 4:
+			crosses++
+		}
+	}
+	return corners, tees, crosses
+}
+
+// straight returns true if a cell has exactly two passages, on opposite sides
+func (c *Cell) straight() bool {
+	return c.linkCount() == 2 &&
+		((c.Linked(c.North) && c.Linked(c.South)) || (c.Linked(c.East) && c.Linked(c.West)))
+}
+
+// Stats summarizes the texture of a maze by counting its cells according to
+// how many passages lead out of them
+type Stats struct {
+	// DeadEnds counts the cells with one passage
+	DeadEnds int
+	// Corridors counts the cells with two passages, whether straight or turning
+	Corridors int
+	// TJunctions counts the cells with three passages
+	TJunctions int
+	// Crossroads counts the cells with four passages
+	Crossroads int
+	// River is the fraction of cells whose two passages continue straight
+	// through them.  Mazes with long straight runs have a high river factor
+	River float64
+}
+
+// Analyze computes statistics describing the texture of the maze.  Cells which
+// are linked to the grid without being part of it, such as those running
+// beneath a weave, are counted along with the cells of the grid
+func Analyze(g *Grid) Stats {
+	cells := g.Cells()
+	offGrid := map[*Cell]bool{}
+	for _, cell := range g.Cells() {
+		for _, l := range cell.Links() {
+			if g.At(l.Row, l.Column) != l && !offGrid[l] {
+				offGrid[l] = true
+				cells = append(cells, l)
+			}
+		}
+	}
+
+	stats := Stats{}
+	straight := 0
+	for _, cell := range cells {
+		switch cell.linkCount() {
+		case 1:
+			stats.DeadEnds++
+		case 2:
+			stats.Corridors++
+			if cell.straight() {
+				straight++
+			}
+		case 3:
+			stats.TJunctions++
+		case 4:
+			stats.Crossroads++
+		}
+	}
+	if len(cells) > 0 {
+		stats.River = float64(straight) / float64(len(cells))
+	}
+	return stats
+}
+
+// passageCount returns the number of pairs of neighboring cells which are linked
+func (g *Grid) passageCount() int64 {
+	count := int64(0)
+	ForEachAdjacentPair(g, func(a, b *Cell) {
+		if a.Linked(b) {
+			count++
+		}
+	})
+	return count
+}
+
+// Loopiness measures how many loops a maze contains, independent of its size.
+// The number of independent loops in the maze (its cyclomatic number) is
+// divided by the number of loops in the same grid with every wall removed, so
+// a perfect maze scores 0 and a fully open grid scores 1.  A mask may split the
+// open grid into several separate regions, each of which needs one fewer
+// passage than it has cells to connect it
+func Loopiness(g *Grid) float64 {
+	pairs := int64(0)
+	ForEachAdjacentPair(g, func(a, b *Cell) {
+		pairs++
+	})
+	regions := int64(0)
+	seen := map[*Cell]bool{}
+	for _, cell := range g.Cells() {
+		if !seen[cell] {
+			regions++
+			for c := range region(cell) {
+				seen[c] = true
+			}
+		}
+	}
+	maxLoops := pairs - g.Size() + regions
+	if maxLoops <= 0 {
+		return 0
+	}
+	loops := g.passageCount() - (g.Size() - int64(len(components(g))))
+	return float64(loops) / float64(maxLoops)
+}
+
+// DeadEnds returns the cells of the grid, in row-major order, which have exactly
+// one passage leading out of them
+func DeadEnds(g *Grid) []*Cell {
+	cells := []*Cell{}
+	for _, cell := range g.Cells() {
+		if cell.linkCount() == 1 {
+			cells = append(cells, cell)
+		}
+	}
+	return cells
+}
+
+// DeepestDeadEnds returns up to n dead ends reachable from root, ordered from
+// the farthest to the nearest.  Dead ends at equal distances are returned in
+// row-major order.  These make good hiding places for treasure
+func DeepestDeadEnds(g *Grid, root *Cell, n int) []*Cell {
+	if root == nil || n <= 0 {
+		return []*Cell{}
+	}
+	dist := ComputeDistances(root)
+	ends := []*Cell{}
+	for _, cell := range DeadEnds(g) {
+		if _, ok := dist.Get(cell); ok {
+			ends = append(ends, cell)
+		}
+	}
+	sort.SliceStable(ends, func(i, j int) bool {
+		return dist.cells[ends[i]] > dist.cells[ends[j]]
+	})
+	if len(ends) > n {
+		ends = ends[:n]
+	}
+	return ends
+}
+
+// IsPerfect returns true if the maze is a spanning tree of the grid: every cell
+// can be reached from every other cell by exactly one route.  This holds when
+// all cells are connected by one fewer links than there are cells, all of which
+// run in both directions.  Cells which are linked to the grid without being
+// part of it, such as those running beneath a weave, belong to the tree as
+// well.  An empty grid is perfect
+func IsPerfect(g *Grid) bool {
+	start := g.firstCell()
+	if start == nil {
+		return true
+	}
+
+	visited := map[*Cell]bool{start: true}
+	queue := []*Cell{start}
+	onGrid := int64(1)
+	ends := int64(0)
+	for i := 0; i < len(queue); i++ {
+		for _, n := range queue[i].Links() {
+			if !n.Linked(queue[i]) {
+				return false
+			}
+			ends++
+			if !visited[n] {
+				visited[n] = true
+				queue = append(queue, n)
+				if g.At(n.Row, n.Column) == n {
+					onGrid++
+				}
+			}
+		}
+	}
+	// Each link was counted once from each of its ends
+	return onGrid == g.Size() && ends/2 == int64(len(visited))-1
+}
+
+// cellID returns the position of a cell in row-major order, which identifies it
+// within the grid
+func (g *Grid) cellID(c *Cell) int64 {
+	return c.Row*g.Columns + c.Column
+}
+
+// AdjacencyList returns the maze as a graph.  Each cell is identified by
+// row*Columns+column, and is mapped to the ids of the cells it is linked to.
+// Linked neighbors are listed in the order north, south, east, west
+func (g *Grid) AdjacencyList() map[int64][]int64 {
+	adjacency := make(map[int64][]int64, g.Size())
+	for _, cell := range g.Cells() {
+		ids := []int64{}
+		for _, l := range cell.Links() {
+			if g.At(l.Row, l.Column) == l {
+				ids = append(ids, g.cellID(l))
+			}
+		}
+		adjacency[g.cellID(cell)] = ids
+	}
+	return adjacency
+}
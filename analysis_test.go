@@ -0,0 +1,408 @@
+package maze
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestIsPerfect(t *testing.T) {
+	tests := []struct {
+		name string
+		grid func() *Grid
+		want bool
+	}{
+		{"Empty", func() *Grid { g := NewGrid(0, 0); return &g }, true},
+		{"SingleCell", func() *Grid { g := NewGrid(1, 1); return &g }, true},
+		{"Unlinked", func() *Grid { g := NewGrid(2, 2); return &g }, false},
+		{"Backtracker", func() *Grid {
+			g := NewGrid(8, 8)
+			RecursiveBacktrackerRand(&g, rand.New(rand.NewSource(1)))
+			return &g
+		}, true},
+		{"Braided", func() *Grid {
+			g := NewGrid(8, 8)
+			r := rand.New(rand.NewSource(1))
+			RecursiveBacktrackerRand(&g, r)
+			Braid(&g, 1, r)
+			return &g
+		}, false},
+		{"IsolatedCell", func() *Grid {
+			g := NewGrid(2, 2)
+			g.At(0, 0).Link(g.At(0, 1))
+			g.At(0, 1).Link(g.At(1, 1))
+			return &g
+		}, false},
+		{"Loop", func() *Grid {
+			g := NewGrid(2, 2)
+			g.At(0, 0).Link(g.At(0, 1))
+			g.At(0, 1).Link(g.At(1, 1))
+			g.At(1, 1).Link(g.At(1, 0))
+			g.At(1, 0).Link(g.At(0, 0))
+			return &g
+		}, false},
+		{"OneWayLink", func() *Grid {
+			g := NewGrid(1, 2)
+			g.At(0, 0).LinkOneWay(g.At(0, 1))
+			return &g
+		}, false},
+		{"Masked", func() *Grid {
+			g := NewMaskedGrid(maskFromString(t, "..\n.X"))
+			g.At(0, 0).Link(g.At(0, 1))
+			g.At(0, 0).Link(g.At(1, 0))
+			return g
+		}, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := IsPerfect(tc.grid()); got != tc.want {
+				t.Errorf("IsPerfect() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPassageCentroid(t *testing.T) {
+	corner := [][4]int64{{0, 0, 0, 1}, {0, 1, 1, 1}, {1, 1, 1, 0}, {1, 0, 0, 0}, {1, 1, 2, 1}}
+	tests := []struct {
+		name     string
+		grid     *Grid
+		row, col float64
+	}{
+		{"Unlinked", linkedGrid(3, 5, nil), 1, 2},
+		{"FullyLinked", func() *Grid { g := NewFullyLinkedGrid(5, 5); return &g }(), 2, 2},
+		{"SingleLink", linkedGrid(4, 4, [][4]int64{{0, 0, 0, 1}}), 0, 0.5},
+		// The ten ends of the five links all lie in the top left corner
+		{"Corner", linkedGrid(6, 6, corner), 0.7, 0.6},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if row, col := tc.grid.PassageCentroid(); row != tc.row || col != tc.col {
+				t.Errorf("PassageCentroid() = %v, %v, want %v, %v", row, col, tc.row, tc.col)
+			}
+		})
+	}
+}
+
+func TestMinGridForPathLength(t *testing.T) {
+	tests := []struct {
+		pathLen    int64
+		rows, cols int64
+	}{
+		{-1, 1, 1},
+		{0, 1, 1},
+		{1, 1, 2},
+		{3, 2, 2},
+		{4, 2, 3},
+		{8, 3, 3},
+		{9, 3, 4},
+		{99, 10, 10},
+		{100, 10, 11},
+	}
+	for _, tc := range tests {
+		if rows, cols := MinGridForPathLength(tc.pathLen); rows != tc.rows || cols != tc.cols {
+			t.Errorf("MinGridForPathLength(%d) = %d, %d, want %d, %d", tc.pathLen, rows, cols, tc.rows, tc.cols)
+		}
+	}
+
+	for pathLen := int64(1); pathLen <= 500; pathLen++ {
+		rows, cols := MinGridForPathLength(pathLen)
+		// A path visiting every cell is the longest possible diameter
+		if rows*cols-1 < pathLen {
+			t.Errorf("MinGridForPathLength(%d) = %d, %d, whose diameter is at most %d", pathLen, rows, cols, rows*cols-1)
+		}
+		if cols-rows != 0 && cols-rows != 1 {
+			t.Errorf("MinGridForPathLength(%d) = %d, %d, which is not near-square", pathLen, rows, cols)
+		}
+		// The next smaller near-square grid is too small
+		smaller := (cols - 1) * rows
+		if rows == cols {
+			smaller = rows * (rows - 1)
+		}
+		if smaller-1 >= pathLen {
+			t.Errorf("MinGridForPathLength(%d) = %d, %d, but a grid of %d cells is large enough", pathLen, rows, cols, smaller)
+		}
+	}
+}
+
+func TestShortcutWalls(t *testing.T) {
+	tests := []struct {
+		name string
+		k    int
+		want [][2][2]int64
+	}{
+		{"None", 0, [][2][2]int64{}},
+		{"Best", 1, [][2][2]int64{{{0, 0}, {1, 0}}}},
+		// Each wall between the rows of the serpentine shortens the route, by 4
+		// steps at either end and by 2 in the middle
+		{"All", 10, [][2][2]int64{{{0, 0}, {1, 0}}, {{1, 2}, {2, 2}}, {{0, 1}, {1, 1}}, {{1, 1}, {2, 1}}}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := serpentine(3, 3)
+			got := [][2][2]int64{}
+			for _, wall := range ShortcutWalls(g, g.At(0, 0), g.At(2, 2), tc.k) {
+				got = append(got, [2][2]int64{{wall[0].Row, wall[0].Column}, {wall[1].Row, wall[1].Column}})
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ShortcutWalls() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestShortcutWallsShortenTheRoute(t *testing.T) {
+	for seed := int64(0); seed < 10; seed++ {
+		g := NewGrid(8, 8)
+		RecursiveBacktrackerRand(&g, rand.New(rand.NewSource(seed)))
+		start, goal := g.At(0, 0), g.At(7, 7)
+		before, _ := ShortestPath(start, goal)
+		walls := ShortcutWalls(&g, start, goal, 3)
+		if len(walls) == 0 {
+			// Only a straight route can't be shortened
+			if len(before) != 15 {
+				t.Errorf("seed %d: no shortcuts found for a route of %d cells", seed, len(before))
+			}
+			continue
+		}
+		walls[0][0].Link(walls[0][1])
+		if after, _ := ShortestPath(start, goal); len(after) >= len(before) {
+			t.Errorf("seed %d: removing the best wall left the route %d cells long, was %d", seed, len(after), len(before))
+		}
+	}
+}
+
+func TestAllCellsConnected(t *testing.T) {
+	tests := []struct {
+		name string
+		grid func() *Grid
+		want bool
+	}{
+		{"Empty", func() *Grid { g := NewGrid(0, 0); return &g }, true},
+		{"SingleCell", func() *Grid { g := NewGrid(1, 1); return &g }, true},
+		{"Backtracker", func() *Grid {
+			g := NewGrid(6, 6)
+			RecursiveBacktrackerRand(&g, rand.New(rand.NewSource(1)))
+			return &g
+		}, true},
+		{"IsolatedCell", func() *Grid {
+			g := NewGrid(6, 6)
+			RecursiveBacktrackerRand(&g, rand.New(rand.NewSource(1)))
+			for _, n := range g.At(3, 3).Links() {
+				g.At(3, 3).Unlink(n)
+			}
+			return &g
+		}, false},
+		// Every cell has a passage even though the maze is in two pieces
+		{"TwoPieces", func() *Grid { return linkedGrid(2, 2, [][4]int64{{0, 0, 0, 1}, {1, 0, 1, 1}}) }, true},
+		{"Masked", func() *Grid {
+			g := NewMaskedGrid(maskFromString(t, ".X\n.."))
+			g.At(0, 0).Link(g.At(1, 0))
+			g.At(1, 0).Link(g.At(1, 1))
+			return g
+		}, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.grid().AllCellsConnected(); got != tc.want {
+				t.Errorf("AllCellsConnected() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWallDensity(t *testing.T) {
+	tests := []struct {
+		name string
+		grid func() *Grid
+		want float64
+	}{
+		{"Empty", func() *Grid { return linkedGrid(0, 0, nil) }, 0},
+		{"SingleCell", func() *Grid { return linkedGrid(1, 1, nil) }, 4},
+		// 12 unit walls around and between four cells
+		{"Unlinked", func() *Grid { return linkedGrid(2, 2, nil) }, 3},
+		{"Serpentine", func() *Grid { return serpentine(2, 2) }, 9.0 / 4},
+		{"Loop", func() *Grid {
+			return linkedGrid(2, 2, [][4]int64{{0, 0, 0, 1}, {0, 1, 1, 1}, {1, 1, 1, 0}, {1, 0, 0, 0}})
+		}, 2},
+		// Only the walls around the three enabled cells are standing
+		{"Masked", func() *Grid {
+			g := NewMaskedGrid(maskFromString(t, ".X\n.."))
+			g.At(0, 0).Link(g.At(1, 0))
+			g.At(1, 0).Link(g.At(1, 1))
+			return g
+		}, 8.0 / 3},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := WallDensity(tc.grid()); got != tc.want {
+				t.Errorf("WallDensity() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWallDensityPerfectMaze(t *testing.T) {
+	// Every perfect maze on the same grid removes the same number of walls, so
+	// they all have the same density
+	g := NewGrid(5, 8)
+	RecursiveBacktrackerRand(&g, rand.New(rand.NewSource(1)))
+	walls := 5*9 + 8*6 - (g.Size() - 1)
+	if got, want := WallDensity(&g), float64(walls)/float64(g.Size()); got != want {
+		t.Errorf("WallDensity() = %v, want %v", got, want)
+	}
+}
+
+func TestJunctionProfile(t *testing.T) {
+	tests := []struct {
+		name                   string
+		grid                   *Grid
+		corners, tees, crosses int
+	}{
+		{"Empty", linkedGrid(0, 0, nil), 0, 0, 0},
+		{"Unlinked", linkedGrid(3, 3, nil), 0, 0, 0},
+		// Two dead ends, two straights, and two corners
+		{"Serpentine", serpentine(2, 3), 2, 0, 0},
+		{"Loop", linkedGrid(2, 2, [][4]int64{{0, 0, 0, 1}, {0, 1, 1, 1}, {1, 1, 1, 0}, {1, 0, 0, 0}}), 4, 0, 0},
+		// A plus sign in a 3x3 grid
+		{"Cross", linkedGrid(3, 3, [][4]int64{{1, 1, 0, 1}, {1, 1, 2, 1}, {1, 1, 1, 0}, {1, 1, 1, 2}}), 0, 0, 1},
+		// A corridor across the top with a branch down the middle
+		{"Tee", linkedGrid(2, 3, [][4]int64{{0, 0, 0, 1}, {0, 1, 0, 2}, {0, 1, 1, 1}}), 0, 1, 0},
+		{"Comb", comb(), 2, 3, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			corners, tees, crosses := tc.grid.JunctionProfile()
+			if corners != tc.corners || tees != tc.tees || crosses != tc.crosses {
+				t.Errorf("JunctionProfile() = %d, %d, %d, want %d, %d, %d", corners, tees, crosses, tc.corners, tc.tees, tc.crosses)
+			}
+		})
+	}
+}
+
+func TestLoopiness(t *testing.T) {
+	perfect := NewGrid(8, 8)
+	RecursiveBacktrackerRand(&perfect, rand.New(rand.NewSource(1)))
+	braided := perfect.Clone()
+	Braid(braided, 0.5, rand.New(rand.NewSource(1)))
+	open := NewFullyLinkedGrid(8, 8)
+
+	// Two 2x2 blocks which can never be joined, each holding a single loop
+	blocks := NewMaskedGrid(maskFromString(t, "..X..\n..X.."))
+	ForEachAdjacentPair(blocks, func(a, b *Cell) { a.Link(b) })
+	halfOpen := NewMaskedGrid(maskFromString(t, "..X..\n..X.."))
+	ForEachAdjacentPair(halfOpen, func(a, b *Cell) {
+		if a.Column < 2 {
+			a.Link(b)
+		}
+	})
+
+	tests := []struct {
+		name string
+		grid *Grid
+		min  float64
+		max  float64
+	}{
+		{"Empty", linkedGrid(0, 0, nil), 0, 0},
+		{"Corridor", serpentine(1, 5), 0, 0},
+		{"Perfect", &perfect, 0, 0},
+		{"Braided", braided, 0.01, 0.99},
+		{"Open", &open, 1, 1},
+		{"MaskedOpen", blocks, 1, 1},
+		{"MaskedHalfOpen", halfOpen, 0.5, 0.5},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Loopiness(tc.grid); got < tc.min || got > tc.max {
+				t.Errorf("Loopiness() = %v, want between %v and %v", got, tc.min, tc.max)
+			}
+		})
+	}
+}
+
+func TestDeadEnds(t *testing.T) {
+	masked := NewMaskedGrid(maskFromString(t, ".X.\n..."))
+	masked.At(0, 0).Link(masked.At(1, 0))
+	masked.At(1, 0).Link(masked.At(1, 1))
+	tests := []struct {
+		name string
+		grid *Grid
+		want [][2]int64
+	}{
+		{"Empty", linkedGrid(0, 0, nil), [][2]int64{}},
+		{"Unlinked", linkedGrid(2, 2, nil), [][2]int64{}},
+		{"Serpentine", serpentine(3, 3), [][2]int64{{0, 0}, {2, 2}}},
+		{"Loop", linkedGrid(2, 2, [][4]int64{{0, 0, 0, 1}, {0, 1, 1, 1}, {1, 1, 1, 0}, {1, 0, 0, 0}}), [][2]int64{}},
+		{"Comb", comb(), [][2]int64{{0, 0}, {0, 4}, {1, 3}, {3, 1}, {3, 3}}},
+		{"Masked", masked, [][2]int64{{0, 0}, {1, 1}}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := positions(DeadEnds(tc.grid)); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("DeadEnds() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDeepestDeadEnds(t *testing.T) {
+	pieces := linkedGrid(1, 5, [][4]int64{{0, 0, 0, 1}, {0, 3, 0, 4}})
+	tests := []struct {
+		name string
+		grid *Grid
+		root [2]int64
+		n    int
+		want [][2]int64
+	}{
+		{"Deepest", comb(), [2]int64{0, 0}, 1, [][2]int64{{3, 3}}},
+		// Ties are broken in row-major order
+		{"Ties", comb(), [2]int64{0, 0}, 3, [][2]int64{{3, 3}, {0, 4}, {1, 3}}},
+		{"All", comb(), [2]int64{0, 0}, 10, [][2]int64{{3, 3}, {0, 4}, {1, 3}, {3, 1}, {0, 0}}},
+		{"OtherRoot", comb(), [2]int64{3, 3}, 10, [][2]int64{{3, 1}, {0, 0}, {0, 4}, {1, 3}, {3, 3}}},
+		{"None", comb(), [2]int64{0, 0}, 0, [][2]int64{}},
+		{"Unreachable", pieces, [2]int64{0, 0}, 10, [][2]int64{{0, 1}, {0, 0}}},
+		{"NoDeadEnds", linkedGrid(2, 2, [][4]int64{{0, 0, 0, 1}, {0, 1, 1, 1}, {1, 1, 1, 0}, {1, 0, 0, 0}}), [2]int64{0, 0}, 10, [][2]int64{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := positions(DeepestDeadEnds(tc.grid, tc.grid.At(tc.root[0], tc.root[1]), tc.n)); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("DeepestDeadEnds() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+	if got := DeepestDeadEnds(comb(), nil, 10); len(got) != 0 {
+		t.Errorf("DeepestDeadEnds(nil) = %v, want no cells", positions(got))
+	}
+}
+
+func TestAdjacencyList(t *testing.T) {
+	tests := []struct {
+		name string
+		grid func() *Grid
+		want map[int64][]int64
+	}{
+		{"Cross", func() *Grid {
+			return linkedGrid(3, 3, [][4]int64{{1, 1, 0, 1}, {1, 1, 2, 1}, {1, 1, 1, 2}, {1, 1, 1, 0}})
+		}, map[int64][]int64{0: {}, 1: {4}, 2: {}, 3: {4}, 4: {1, 7, 5, 3}, 5: {4}, 6: {}, 7: {4}, 8: {}}},
+		{"Masked", func() *Grid {
+			g := NewMaskedGrid(maskFromString(t, "..\nX."))
+			g.At(0, 0).Link(g.At(0, 1))
+			g.At(0, 1).Link(g.At(1, 1))
+			return g
+		}, map[int64][]int64{0: {1}, 1: {3, 0}, 3: {1}}},
+		{"Cylinder", func() *Grid {
+			g := NewCylinderGrid(1, 3)
+			g.At(0, 0).Link(g.At(0, 2))
+			return &g
+		}, map[int64][]int64{0: {2}, 1: {}, 2: {0}}},
+		// The passage beneath the grid is left out, since its cell has no id
+		{"Weave", func() *Grid { return &crossing(t).Grid }, map[int64][]int64{0: {}, 1: {}, 2: {}, 3: {4}, 4: {5, 3}, 5: {4}, 6: {}, 7: {}, 8: {}}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.grid().AdjacencyList(); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("AdjacencyList() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package maze
+
+import (
+	"container/heap"
+)
+
+// aStarItem is a cell waiting to be explored by AStar
+type aStarItem struct {
+	cell *Cell
+	// The number of steps from the start to the cell
+	cost int64
+	// The cost plus the estimated number of steps from the cell to the goal
+	estimate int64
+}
+
+// aStarQueue is a priority queue of cells ordered by their estimated total
+// route length
+type aStarQueue []aStarItem
+
+// Len returns the number of cells in the queue
+func (q aStarQueue) Len() int {
+	return len(q)
+}
+
+// Less orders cells by their estimated route length
+func (q aStarQueue) Less(i, j int) bool {
+	if q[i].estimate != q[j].estimate {
+		return q[i].estimate < q[j].estimate
+	}
+	// Prefer cells nearer the goal when the estimates are tied
+	return q[i].cost > q[j].cost
+}
+
+// Swap exchanges two cells in the queue
+func (q aStarQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+// Push adds a cell to the end of the queue
+func (q *aStarQueue) Push(x interface{}) {
+	*q = append(*q, x.(aStarItem))
+}
+
+// Pop removes the cell at the end of the queue
+func (q *aStarQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// manhattan returns the number of rows plus the number of columns between two
+// cells
+func manhattan(a, b *Cell) int64 {
+	return int64(abs(int(a.Row-b.Row)) + abs(int(a.Column-b.Column)))
+}
+
+// aStarHeuristic estimates the number of steps from a cell to the goal without
+// ever overestimating it, which keeps the routes AStar finds the shortest
+type aStarHeuristic struct {
+	goal *Cell
+	// The number of rows and columns after which the grid wraps around, or zero
+	// if it doesn't wrap in that direction
+	rows, columns int64
+}
+
+// newAStarHeuristic creates a heuristic for reaching the goal in a grid, taking
+// any joined edges of the grid into account
+func newAStarHeuristic(g *Grid, goal *Cell) aStarHeuristic {
+	h := aStarHeuristic{goal: goal}
+	if g.cylinder {
+		h.columns = g.Columns
+	}
+	if g.torus {
+		h.rows = g.Rows
+	}
+	return h
+}
+
+// estimate returns the fewest steps in which a cell could reach the goal: the
+// number of rows plus the number of columns between them, going the short way
+// around any joined edges
+func (h aStarHeuristic) estimate(c *Cell) int64 {
+	rows := int64(abs(int(c.Row - h.goal.Row)))
+	if h.rows > 0 && h.rows-rows < rows {
+		rows = h.rows - rows
+	}
+	columns := int64(abs(int(c.Column - h.goal.Column)))
+	if h.columns > 0 && h.columns-columns < columns {
+		columns = h.columns - columns
+	}
+	return rows + columns
+}
+
+// AStar returns the cells along a shortest route through linked passages from
+// one cell of a grid to another, including both ends, like ShortestPath.  It
+// uses the A* search algorithm, guided by the Manhattan distance to the goal, so
+// it usually explores far fewer cells.  On a cylinder or torus the distance is
+// measured the short way around the joined edges.  Links between cells which
+// aren't neighbors, which no generator carves, are followed, but the route
+// across them may not be the shortest.  If the destination can't be reached, it
+// returns nil and false
+func AStar(g *Grid, from, to *Cell) ([]*Cell, bool) {
+	path, ok, _ := aStar(g, from, to)
+	return path, ok
+}
+
+// aStar implements AStar, also returning the number of cells it explored
+func aStar(g *Grid, from, to *Cell) ([]*Cell, bool, int) {
+	h := newAStarHeuristic(g, to)
+	cost := map[*Cell]int64{from: 0}
+	prev := map[*Cell]*Cell{from: nil}
+	open := &aStarQueue{{cell: from, estimate: h.estimate(from)}}
+	explored := 0
+	for open.Len() > 0 {
+		item := heap.Pop(open).(aStarItem)
+		if item.cell == to {
+			return tracePath(prev, to), true, explored
+		}
+		if item.cost > cost[item.cell] {
+			// A shorter route to this cell has already been explored
+			continue
+		}
+		explored++
+		for _, n := range item.cell.Links() {
+			c := item.cost + 1
+			if old, ok := cost[n]; ok && old <= c {
+				continue
+			}
+			cost[n], prev[n] = c, item.cell
+			heap.Push(open, aStarItem{cell: n, cost: c, estimate: c + h.estimate(n)})
+		}
+	}
+	return nil, false, explored
+}
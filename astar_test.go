@@ -0,0 +1,158 @@
+package maze
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestAStarMatchesShortestPath(t *testing.T) {
+	braided := func(g Grid, r *rand.Rand) *Grid {
+		RecursiveBacktrackerRand(&g, r)
+		Braid(&g, 1, r)
+		return &g
+	}
+	tests := []struct {
+		name string
+		grid func(r *rand.Rand) *Grid
+	}{
+		{"Perfect", func(r *rand.Rand) *Grid {
+			g := NewGrid(12, 15)
+			RecursiveBacktrackerRand(&g, r)
+			return &g
+		}},
+		{"Braided", func(r *rand.Rand) *Grid { return braided(NewGrid(12, 15), r) }},
+		{"Cylinder", func(r *rand.Rand) *Grid { return braided(NewCylinderGrid(12, 15), r) }},
+		{"Torus", func(r *rand.Rand) *Grid { return braided(NewTorusGrid(12, 15), r) }},
+		{"Masked", func(r *rand.Rand) *Grid {
+			g := NewMaskedGrid(maskFromString(t, "......\n.XX...\n....X.\n......"))
+			RecursiveBacktrackerRand(g, r)
+			return g
+		}},
+		{"Weave", func(r *rand.Rand) *Grid {
+			w := NewWeaveGrid(12, 15)
+			WeaveBacktrackerRand(w, r)
+			return &w.Grid
+		}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for seed := int64(0); seed < 5; seed++ {
+				r := rand.New(rand.NewSource(seed))
+				g := tc.grid(r)
+				for i := 0; i < 50; i++ {
+					from, to := g.RandomCellRand(r), g.RandomCellRand(r)
+					want, _ := ShortestPath(from, to)
+					got, ok := AStar(g, from, to)
+					if !ok || len(got) != len(want) {
+						t.Fatalf("seed %d: AStar([%d, %d], [%d, %d]) took %d steps, want %d:\n%s", seed, from.Row, from.Column, to.Row, to.Column, len(got)-1, len(want)-1, g.ToStringWithPath(got, from, to))
+					}
+					if got[0] != from || got[len(got)-1] != to {
+						t.Fatalf("seed %d: AStar() = %v, which doesn't join the ends", seed, positions(got))
+					}
+					for j := 1; j < len(got); j++ {
+						if !got[j-1].Linked(got[j]) {
+							t.Fatalf("seed %d: AStar() = %v, which crosses a wall", seed, positions(got))
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestAStarUnreachable(t *testing.T) {
+	g := linkedGrid(1, 3, [][4]int64{{0, 0, 0, 1}})
+	if path, ok := AStar(g, g.At(0, 0), g.At(0, 2)); ok || path != nil {
+		t.Errorf("AStar() = %v, %v, want nil, false", positions(path), ok)
+	}
+	if path, ok := AStar(g, g.At(0, 1), g.At(0, 1)); !ok || len(path) != 1 {
+		t.Errorf("AStar() to the same cell = %v, %v, want the cell alone", positions(path), ok)
+	}
+}
+
+func TestAStarNonNeighborLinks(t *testing.T) {
+	// Links between cells which aren't neighbors are followed, even though the
+	// route across them may not be the shortest
+	r := rand.New(rand.NewSource(1))
+	g := NewGrid(12, 15)
+	RecursiveBacktrackerRand(&g, r)
+	for i := 0; i < 5; i++ {
+		g.RandomCellRand(r).Link(g.RandomCellRand(r))
+	}
+	for i := 0; i < 50; i++ {
+		from, to := g.RandomCellRand(r), g.RandomCellRand(r)
+		got, ok := AStar(&g, from, to)
+		if !ok || got[0] != from || got[len(got)-1] != to {
+			t.Fatalf("AStar([%d, %d], [%d, %d]) = %v, %v, want a route between them", from.Row, from.Column, to.Row, to.Column, positions(got), ok)
+		}
+		for j := 1; j < len(got); j++ {
+			if !got[j-1].Linked(got[j]) {
+				t.Fatalf("AStar() = %v, which crosses a wall", positions(got))
+			}
+		}
+	}
+}
+
+func TestAStarHeuristic(t *testing.T) {
+	torus := NewTorusGrid(5, 7)
+	cylinder := NewCylinderGrid(5, 7)
+	flat := NewGrid(5, 7)
+	tests := []struct {
+		name     string
+		grid     *Grid
+		from     [2]int64
+		estimate int64
+	}{
+		{"Flat", &flat, [2]int64{4, 6}, 10},
+		{"Cylinder", &cylinder, [2]int64{4, 6}, 5},
+		{"Torus", &torus, [2]int64{4, 6}, 2},
+		{"TorusMiddle", &torus, [2]int64{2, 3}, 5},
+		{"Goal", &torus, [2]int64{0, 0}, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			h := newAStarHeuristic(tc.grid, tc.grid.At(0, 0))
+			if got := h.estimate(tc.grid.At(tc.from[0], tc.from[1])); got != tc.estimate {
+				t.Errorf("estimate(%v) = %d, want %d", tc.from, got, tc.estimate)
+			}
+		})
+	}
+}
+
+func TestAStarExploresFewerCells(t *testing.T) {
+	g := NewFullyLinkedGrid(30, 30)
+	tests := []struct {
+		name     string
+		from, to [2]int64
+	}{
+		{"Corners", [2]int64{0, 0}, [2]int64{29, 29}},
+		{"MiddleToEdge", [2]int64{15, 15}, [2]int64{15, 29}},
+		{"Adjacent", [2]int64{10, 10}, [2]int64{10, 11}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			from, to := g.At(tc.from[0], tc.from[1]), g.At(tc.to[0], tc.to[1])
+			path, ok, explored := aStar(&g, from, to)
+			want, _ := ShortestPath(from, to)
+			if !ok || len(path) != len(want) {
+				t.Fatalf("AStar() took %d steps, want %d", len(path)-1, len(want)-1)
+			}
+			// A breadth-first search explores every cell nearer the start than
+			// the goal before reaching it
+			distances := ComputeDistances(from)
+			goal, _ := distances.Get(to)
+			nearer := 0
+			for _, cell := range g.Cells() {
+				if d, _ := distances.Get(cell); d < goal {
+					nearer++
+				}
+			}
+			if explored >= nearer && nearer > 1 {
+				t.Errorf("AStar() explored %d cells, but a breadth-first search explores %d", explored, nearer)
+			}
+			if explored > 2*len(path) {
+				t.Errorf("AStar() explored %d cells to find a route of %d on an open grid", explored, len(path))
+			}
+		})
+	}
+}
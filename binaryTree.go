@@ -1,25 +1,54 @@
 package maze
 
 import (
+	"fmt"
 	"math/rand"
 )
 
 // BinaryTree uses the binary tree maze creation algorithm to create a maze in a
 // rectangular grid
 func BinaryTree(g *Grid) {
-	for cell := range(g.AllCells()) {
+	BinaryTreeRand(g, defaultRand)
+}
+
+// BinaryTreeRand is BinaryTree using the provided random source
+func BinaryTreeRand(g *Grid, r *rand.Rand) {
+	// North and East are always a valid bias
+	_ = BinaryTreeBiasedRand(g, North, East, r)
+}
+
+// BinaryTreeBiased uses the binary tree maze creation algorithm to create a maze
+// in a rectangular grid, linking each cell to its neighbor in either the
+// vertical or the horizontal direction given.  The edges of the grid on those
+// two sides become unbroken corridors.  On a cylinder or torus, passages never
+// cross the joined edges, since the corridors would otherwise form loops
+func BinaryTreeBiased(g *Grid, vertical, horizontal Direction) error {
+	return BinaryTreeBiasedRand(g, vertical, horizontal, defaultRand)
+}
+
+// BinaryTreeBiasedRand is BinaryTreeBiased using the provided random source
+func BinaryTreeBiasedRand(g *Grid, vertical, horizontal Direction, r *rand.Rand) error {
+	if vertical != North && vertical != South {
+		return fmt.Errorf("vertical bias must be North or South, not %v", vertical)
+	}
+	if horizontal != East && horizontal != West {
+		return fmt.Errorf("horizontal bias must be East or West, not %v", horizontal)
+	}
+
+	for _, cell := range g.Cells() {
 		neighbors := []*Cell{}
-		// Each cell should be randomly linked to either its east or north neighbor
-		if (cell.North != nil) {
-			neighbors = append(neighbors, cell.North)
+		// Each cell should be randomly linked to either its vertical or horizontal neighbor
+		if n := cell.neighbor(vertical); n != nil && !wrapsAround(cell, n) {
+			neighbors = append(neighbors, n)
 		}
 
-		if (cell.East != nil) {
-			neighbors = append(neighbors, cell.East)
+		if n := cell.neighbor(horizontal); n != nil && !wrapsAround(cell, n) {
+			neighbors = append(neighbors, n)
 		}
 
-		if (len(neighbors) > 0) {
-			cell.Link(neighbors[rand.Intn(len(neighbors))])
+		if len(neighbors) > 0 {
+			cell.Link(neighbors[r.Intn(len(neighbors))])
 		}
 	}
-}
\ No newline at end of file
+	return nil
+}
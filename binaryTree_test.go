@@ -0,0 +1,111 @@
+package maze
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestBinaryTree(t *testing.T) {
+	sizes := []struct {
+		rows, columns int64
+	}{
+		{1, 1},
+		{1, 5},
+		{5, 1},
+		{5, 5},
+	}
+	for _, s := range sizes {
+		g := NewGrid(s.rows, s.columns)
+		BinaryTree(&g)
+		ends := 0
+		for cell := range g.AllCells() {
+			chosen := 0
+			if cell.Linked(cell.North) {
+				chosen++
+			}
+			if cell.Linked(cell.East) {
+				chosen++
+			}
+			want := 1
+			if cell.Row == 0 && cell.Column == g.Columns-1 {
+				want = 0
+			}
+			if chosen != want {
+				t.Errorf("%dx%d: cell [%d, %d] is linked to %d of its north and east neighbors, want %d", s.rows, s.columns, cell.Row, cell.Column, chosen, want)
+			}
+			ends += cell.linkCount()
+		}
+		if int64(ends/2) != g.Size()-1 || len(components(&g)) != 1 {
+			t.Errorf("%dx%d: maze is not perfect:\n%s", s.rows, s.columns, g.ToString())
+		}
+	}
+}
+
+func TestBinaryTreeBiased(t *testing.T) {
+	for _, vertical := range []Direction{North, South} {
+		for _, horizontal := range []Direction{East, West} {
+			g := NewGrid(6, 7)
+			if err := BinaryTreeBiasedRand(&g, vertical, horizontal, rand.New(rand.NewSource(1))); err != nil {
+				t.Fatal(err)
+			}
+			name := fmt.Sprintf("%v/%v", vertical, horizontal)
+			if !IsPerfect(&g) {
+				t.Errorf("%s: maze is not perfect:\n%s", name, g.ToString())
+			}
+
+			// The row on the vertical side and the column on the horizontal side
+			// are unbroken corridors
+			row, column := int64(0), g.Columns-1
+			if vertical == South {
+				row = g.Rows - 1
+			}
+			if horizontal == West {
+				column = 0
+			}
+			for c := int64(0); c+1 < g.Columns; c++ {
+				if !g.At(row, c).Linked(g.At(row, c+1)) {
+					t.Errorf("%s: row %d is broken at column %d:\n%s", name, row, c, g.ToString())
+				}
+			}
+			for r := int64(0); r+1 < g.Rows; r++ {
+				if !g.At(r, column).Linked(g.At(r+1, column)) {
+					t.Errorf("%s: column %d is broken at row %d:\n%s", name, column, r, g.ToString())
+				}
+			}
+
+			// Every other cell is linked in exactly one of the chosen directions
+			for _, cell := range g.Cells() {
+				chosen := 0
+				for _, d := range []Direction{vertical, horizontal} {
+					if cell.Linked(cell.neighbor(d)) {
+						chosen++
+					}
+				}
+				if (cell.Row != row || cell.Column != column) && chosen != 1 {
+					t.Errorf("%s: cell [%d, %d] is linked in %d of the chosen directions, want 1", name, cell.Row, cell.Column, chosen)
+				}
+			}
+		}
+	}
+}
+
+func TestBinaryTreeBiasedErrors(t *testing.T) {
+	tests := []struct {
+		vertical, horizontal Direction
+	}{
+		{East, East},
+		{North, North},
+		{West, South},
+		{North, Direction(7)},
+	}
+	for _, tc := range tests {
+		g := NewGrid(3, 3)
+		if err := BinaryTreeBiased(&g, tc.vertical, tc.horizontal); err == nil {
+			t.Errorf("BinaryTreeBiased(%v, %v) succeeded, want an error", tc.vertical, tc.horizontal)
+		}
+		if !Equal(&g, func() *Grid { h := NewGrid(3, 3); return &h }()) {
+			t.Errorf("BinaryTreeBiased(%v, %v) changed the grid:\n%s", tc.vertical, tc.horizontal, g.ToString())
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package maze
+
+import (
+	"math/rand"
+)
+
+// Braid removes dead ends from a maze by linking them to one of their unlinked
+// neighbors, which adds loops.  Each dead end is removed with probability p, so
+// with p = 1 only dead ends without an unlinked neighbor remain.  Where
+// possible a dead end is joined to another dead end, removing both at once
+func Braid(g *Grid, p float64, rng *rand.Rand) {
+	ends := DeadEnds(g)
+	rng.Shuffle(len(ends), func(i, j int) {
+		ends[i], ends[j] = ends[j], ends[i]
+	})
+
+	for _, cell := range ends {
+		// An earlier link may already have removed this dead end
+		if cell.linkCount() != 1 || rng.Float64() >= p {
+			continue
+		}
+
+		unlinked := []*Cell{}
+		preferred := []*Cell{}
+		for _, n := range cell.Neighbors() {
+			if !cell.Linked(n) {
+				unlinked = append(unlinked, n)
+				if n.linkCount() == 1 {
+					preferred = append(preferred, n)
+				}
+			}
+		}
+		if len(preferred) == 0 {
+			preferred = unlinked
+		}
+		if len(preferred) > 0 {
+			cell.Link(preferred[rng.Intn(len(preferred))])
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package maze
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestBraid(t *testing.T) {
+	for seed := int64(0); seed < 5; seed++ {
+		g := NewGrid(10, 10)
+		RecursiveBacktrackerRand(&g, rand.New(rand.NewSource(seed)))
+		before := len(DeadEnds(&g))
+
+		unchanged := g.Clone()
+		Braid(unchanged, 0, rand.New(rand.NewSource(seed)))
+		if !Equal(unchanged, &g) {
+			t.Errorf("seed %d: Braid(0) changed the maze", seed)
+		}
+
+		half := g.Clone()
+		Braid(half, 0.5, rand.New(rand.NewSource(seed)))
+		if after := len(DeadEnds(half)); after >= before {
+			t.Errorf("seed %d: Braid(0.5) left %d of %d dead ends", seed, after, before)
+		}
+
+		// Every cell of a grid at least two cells wide in both directions has a
+		// neighbor it isn't linked to, so every dead end can be removed
+		full := g.Clone()
+		Braid(full, 1, rand.New(rand.NewSource(seed)))
+		if after := len(DeadEnds(full)); after != 0 {
+			t.Errorf("seed %d: Braid(1) left %d dead ends:\n%s", seed, after, full.ToString())
+		}
+
+		for _, braided := range []*Grid{half, full} {
+			if int64(len(Reachable(braided.At(0, 0)))) != braided.Size() {
+				t.Errorf("seed %d: braided maze is disconnected", seed)
+			}
+			ForEachAdjacentPair(&g, func(a, b *Cell) {
+				if a.Linked(b) && !braided.At(a.Row, a.Column).Linked(braided.At(b.Row, b.Column)) {
+					t.Errorf("seed %d: braiding removed the passage [%d, %d]-[%d, %d]", seed, a.Row, a.Column, b.Row, b.Column)
+				}
+			})
+		}
+	}
+}
+
+func TestBraidCorridor(t *testing.T) {
+	// The ends of a single-row corridor have no unlinked neighbors, so they
+	// remain dead ends
+	g := serpentine(1, 5)
+	Braid(g, 1, rand.New(rand.NewSource(1)))
+	if !Equal(g, serpentine(1, 5)) {
+		t.Errorf("Braid() changed a corridor:\n%s", g.ToString())
+	}
+}
@@ -1,25 +1,55 @@
 package maze
 
+import (
+	"sort"
+)
+
 // Cell represents a cell in a rectangular maze
 type Cell struct {
 	// The location of this cell in the Grid
 	Row, Column int64
 	// The immediate neighbors of this cell
 	North, South, East, West *Cell
-	// A set of cells direclty linked to this cell
+	// The directions in which this cell has an open passage to its immediate
+	// neighbor, one bit per Direction.  A set bit means the cells are linked
+	passages uint8
+	// The non-nil neighbors of this cell, cached by Neighbors
+	neighbors []*Cell
+	// A set of cells directly linked to this cell which aren't immediate
+	// neighbors.  Only allocated once such a link is made
 	links map[*Cell]bool
 }
 
 func NewCell(row, column int64) Cell {
 	c := Cell{
 		Row:    row,
-		Column: column,
-		links:  make(map[*Cell]bool)}
+		Column: column}
 	return c
 }
 
+// directionOf returns the direction in which a cell lies if it is an immediate
+// neighbor of this cell
+func (c *Cell) directionOf(neighbor *Cell) (Direction, bool) {
+	if neighbor == nil {
+		return 0, false
+	}
+	for _, d := range []Direction{North, South, East, West} {
+		if c.neighbor(d) == neighbor {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
 // LinkOneWay links one cell to another unidirectionally
 func (c *Cell) LinkOneWay(neighbor *Cell) {
+	if d, ok := c.directionOf(neighbor); ok {
+		c.passages |= 1 << uint(d)
+		return
+	}
+	if c.links == nil {
+		c.links = make(map[*Cell]bool)
+	}
 	c.links[neighbor] = true
 }
 
@@ -37,6 +67,10 @@ func (c *Cell) Unlink(neighbor *Cell) {
 
 // UnlinkOneWay removes the unidirectional link between a cell and its neighbor
 func (c *Cell) UnlinkOneWay(neighbor *Cell) {
+	if d, ok := c.directionOf(neighbor); ok {
+		c.passages &^= 1 << uint(d)
+		return
+	}
 	delete(c.links, neighbor)
 }
 
@@ -45,17 +79,113 @@ func (c *Cell) Linked(neighbor *Cell) bool {
 	if neighbor == nil {
 		return false
 	}
+	if d, ok := c.directionOf(neighbor); ok {
+		return c.passages&(1<<uint(d)) != 0
+	}
 	linked, ok := c.links[neighbor]
 	return ok && linked
 }
 
-// Neighbors returns the list of direct neighbors of this cell
+// hasLinks returns true if this cell is linked to any other cell
+func (c *Cell) hasLinks() bool {
+	return c.passages != 0 || len(c.links) > 0
+}
+
+// clearLinks removes every link from this cell to any other cell
+func (c *Cell) clearLinks() {
+	c.passages = 0
+	c.links = nil
+}
+
+// Neighbors returns the list of direct neighbors of this cell.  The slice is
+// shared between calls and must not be modified
 func (c *Cell) Neighbors() []*Cell {
+	if !c.neighborsCached() {
+		c.cacheNeighbors()
+	}
+	return c.neighbors
+}
+
+// cacheNeighbors records the current neighbors of this cell so that Neighbors
+// doesn't need to allocate a new slice on every call
+func (c *Cell) cacheNeighbors() {
 	ret := []*Cell{}
 	for _, n := range []*Cell{c.North, c.South, c.East, c.West} {
 		if n != nil {
 			ret = append(ret, n)
 		}
 	}
-	return ret
+	// Limit the capacity so that appending to the result never writes into
+	// the cache
+	c.neighbors = ret[:len(ret):len(ret)]
+}
+
+// neighborsCached returns true if the cached neighbors still match the
+// neighbor pointers of this cell, which change when grids are rewired
+func (c *Cell) neighborsCached() bool {
+	if c.neighbors == nil {
+		return false
+	}
+	i := 0
+	for _, n := range [4]*Cell{c.North, c.South, c.East, c.West} {
+		if n == nil {
+			continue
+		}
+		if i >= len(c.neighbors) || c.neighbors[i] != n {
+			return false
+		}
+		i++
+	}
+	return i == len(c.neighbors)
+}
+
+// Links returns the cells this cell is linked to.  Linked neighbors come first,
+// in the same order as Neighbors, followed by any other linked cells ordered by
+// their position in the grid
+func (c *Cell) Links() []*Cell {
+	ret := []*Cell{}
+	for _, n := range c.Neighbors() {
+		if c.Linked(n) {
+			ret = append(ret, n)
+		}
+	}
+	if len(c.links) == 0 {
+		return ret
+	}
+
+	// Some links lead to cells which aren't direct neighbors
+	extra := []*Cell{}
+	for l, linked := range c.links {
+		if linked {
+			extra = append(extra, l)
+		}
+	}
+	sort.Slice(extra, func(i, j int) bool {
+		if extra[i].Row != extra[j].Row {
+			return extra[i].Row < extra[j].Row
+		}
+		return extra[i].Column < extra[j].Column
+	})
+	return append(ret, extra...)
+}
+
+// containsCell returns true if a cell appears in a list of cells
+func containsCell(cells []*Cell, c *Cell) bool {
+	for _, x := range cells {
+		if x == c {
+			return true
+		}
+	}
+	return false
+}
+
+// linkCount returns the number of neighbors this cell is linked to
+func (c *Cell) linkCount() int {
+	count := 0
+	for _, n := range c.Neighbors() {
+		if c.Linked(n) {
+			count++
+		}
+	}
+	return count
 }
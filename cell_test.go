@@ -0,0 +1,175 @@
+package maze
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestCellLinks(t *testing.T) {
+	g := NewGrid(3, 3)
+	center := g.At(1, 1)
+	tests := []struct {
+		name  string
+		other *Cell
+	}{
+		{"North", g.At(0, 1)},
+		{"South", g.At(2, 1)},
+		{"East", g.At(1, 2)},
+		{"West", g.At(1, 0)},
+		{"NotNeighbor", g.At(2, 2)},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g.Reset()
+			if center.Linked(tc.other) || tc.other.Linked(center) {
+				t.Fatal("cells are linked before Link")
+			}
+			center.Link(tc.other)
+			if !center.Linked(tc.other) || !tc.other.Linked(center) {
+				t.Fatal("Link did not link both cells")
+			}
+			if links := center.Links(); len(links) != 1 || links[0] != tc.other {
+				t.Errorf("Links() = %v, want only the linked cell", links)
+			}
+			for _, cell := range g.Cells() {
+				if cell != center && cell != tc.other && cell.hasLinks() {
+					t.Errorf("cell [%d, %d] was linked too", cell.Row, cell.Column)
+				}
+			}
+			center.Unlink(tc.other)
+			if center.Linked(tc.other) || tc.other.Linked(center) || center.hasLinks() || tc.other.hasLinks() {
+				t.Error("Unlink left the cells linked")
+			}
+		})
+	}
+}
+
+func TestCellLinkOneWay(t *testing.T) {
+	g := NewGrid(2, 3)
+	a, neighbor, far := g.At(0, 0), g.At(0, 1), g.At(1, 2)
+	a.LinkOneWay(neighbor)
+	a.LinkOneWay(far)
+	if !a.Linked(neighbor) || !a.Linked(far) || neighbor.Linked(a) || far.Linked(a) {
+		t.Fatal("LinkOneWay linked in the wrong directions")
+	}
+	if links := a.Links(); len(links) != 2 || links[0] != neighbor || links[1] != far {
+		t.Errorf("Links() = %v, want the neighbor followed by the distant cell", links)
+	}
+	a.UnlinkOneWay(far)
+	if a.Linked(far) || !a.Linked(neighbor) {
+		t.Error("UnlinkOneWay removed the wrong link")
+	}
+	if a.Linked(nil) {
+		t.Error("Linked(nil) = true")
+	}
+}
+
+// mapLinkedCell stores every link in a map, as Cell did before links to its
+// neighbors were stored as bits.  It is kept to compare the two
+type mapLinkedCell struct {
+	links map[*mapLinkedCell]bool
+}
+
+func (c *mapLinkedCell) link(other *mapLinkedCell) {
+	c.links[other] = true
+	other.links[c] = true
+}
+
+func (c *mapLinkedCell) linked(other *mapLinkedCell) bool {
+	return c.links[other]
+}
+
+func BenchmarkLinkBitmask(b *testing.B) {
+	g := NewGrid(2, 2)
+	a, n := g.At(0, 0), g.At(0, 1)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		a.Link(n)
+		if !a.Linked(n) {
+			b.Fatal("not linked")
+		}
+		a.Unlink(n)
+	}
+}
+
+func BenchmarkLinkMap(b *testing.B) {
+	a := &mapLinkedCell{links: map[*mapLinkedCell]bool{}}
+	n := &mapLinkedCell{links: map[*mapLinkedCell]bool{}}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		a.link(n)
+		if !a.linked(n) {
+			b.Fatal("not linked")
+		}
+		delete(a.links, n)
+		delete(n.links, a)
+	}
+}
+
+// pointerNeighbors returns the non-nil neighbor pointers of a cell in the order
+// Neighbors uses
+func pointerNeighbors(c *Cell) []*Cell {
+	ret := []*Cell{}
+	for _, n := range []*Cell{c.North, c.South, c.East, c.West} {
+		if n != nil {
+			ret = append(ret, n)
+		}
+	}
+	return ret
+}
+
+func TestNeighborsMatchPointers(t *testing.T) {
+	weave := NewWeaveGrid(3, 3)
+	over := weave.At(1, 1)
+	weave.At(1, 0).Link(over)
+	over.Link(weave.At(1, 2))
+	if err := weave.Tunnel(weave.At(0, 1), weave.At(2, 1)); err != nil {
+		t.Fatal(err)
+	}
+	grown := NewCylinderGrid(2, 4)
+	grown.AppendRow(rand.New(rand.NewSource(1)))
+
+	grids := []struct {
+		name string
+		grid *Grid
+	}{
+		{"Rectangle", func() *Grid { g := NewGrid(3, 4); return &g }()},
+		{"Masked", NewMaskedGrid(maskFromString(t, "X...\n..X.\n...X"))},
+		{"Torus", func() *Grid { g := NewTorusGrid(4, 4); return &g }()},
+		{"Tunneled", &weave.Grid},
+		{"AppendedRow", &grown},
+	}
+	for _, tc := range grids {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, cell := range tc.grid.Cells() {
+				got, want := cell.Neighbors(), pointerNeighbors(cell)
+				if len(got) != len(want) {
+					t.Fatalf("cell [%d, %d] has %d neighbors, want %d", cell.Row, cell.Column, len(got), len(want))
+				}
+				for i := range got {
+					if got[i] != want[i] {
+						t.Errorf("cell [%d, %d] neighbor %d differs from its pointers", cell.Row, cell.Column, i)
+					}
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkNeighborsCached(b *testing.B) {
+	g := NewGrid(3, 3)
+	cell := g.At(1, 1)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = cell.Neighbors()
+	}
+}
+
+func BenchmarkNeighborsUncached(b *testing.B) {
+	g := NewGrid(3, 3)
+	cell := g.At(1, 1)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = pointerNeighbors(cell)
+	}
+}
@@ -0,0 +1,28 @@
+package maze
+
+// Eccentricities returns the eccentricity of every cell in the grid: the number
+// of steps from the cell to the farthest cell reachable from it
+func Eccentricities(g *Grid) map[*Cell]int {
+	ecc := map[*Cell]int{}
+	for _, cell := range g.Cells() {
+		_, farthest := ComputeDistances(cell).Max()
+		ecc[cell] = int(farthest)
+	}
+	return ecc
+}
+
+// GraphCenter returns the cells with the smallest eccentricity, in row-major
+// order.  These are the cells from which the rest of the maze is most evenly
+// reachable; a perfect maze has either one center cell or two adjacent ones
+func GraphCenter(g *Grid) []*Cell {
+	ecc := Eccentricities(g)
+	center := []*Cell{}
+	for _, cell := range g.Cells() {
+		if len(center) == 0 || ecc[cell] < ecc[center[0]] {
+			center = []*Cell{cell}
+		} else if ecc[cell] == ecc[center[0]] {
+			center = append(center, cell)
+		}
+	}
+	return center
+}
@@ -0,0 +1,58 @@
+package maze
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEccentricities(t *testing.T) {
+	tests := []struct {
+		name string
+		grid *Grid
+		want [][]int
+	}{
+		{"SingleCell", linkedGrid(1, 1, nil), [][]int{{0}}},
+		{"Path", serpentine(1, 5), [][]int{{4, 3, 2, 3, 4}}},
+		{"Serpentine", serpentine(2, 2), [][]int{{3, 2}, {3, 2}}},
+		// The cells below the ends of the T are walled in
+		{"Tee", linkedGrid(2, 3, [][4]int64{{0, 0, 0, 1}, {0, 1, 0, 2}, {0, 1, 1, 1}}), [][]int{{2, 1, 2}, {0, 2, 0}}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ecc := Eccentricities(tc.grid)
+			if len(ecc) != int(tc.grid.Size()) {
+				t.Errorf("Eccentricities() has %d cells, want %d", len(ecc), tc.grid.Size())
+			}
+			for r, row := range tc.want {
+				for c, want := range row {
+					if got := ecc[tc.grid.At(int64(r), int64(c))]; got != want {
+						t.Errorf("eccentricity of [%d, %d] = %d, want %d", r, c, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGraphCenter(t *testing.T) {
+	tests := []struct {
+		name string
+		grid *Grid
+		want [][2]int64
+	}{
+		{"Empty", linkedGrid(0, 0, nil), [][2]int64{}},
+		{"SingleCell", linkedGrid(1, 1, nil), [][2]int64{{0, 0}}},
+		{"OddPath", serpentine(1, 5), [][2]int64{{0, 2}}},
+		{"EvenPath", serpentine(1, 4), [][2]int64{{0, 1}, {0, 2}}},
+		{"Serpentine", serpentine(3, 3), [][2]int64{{1, 1}}},
+		{"Star", linkedGrid(3, 3, [][4]int64{{1, 1, 0, 1}, {1, 1, 1, 0}, {1, 1, 1, 2}, {1, 1, 2, 1},
+			{0, 1, 0, 0}, {1, 2, 0, 2}, {2, 1, 2, 2}, {1, 0, 2, 0}}), [][2]int64{{1, 1}}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := positions(GraphCenter(tc.grid)); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("GraphCenter() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+package maze
+
+// contextCheckInterval is the number of steps a cancellable generator takes
+// between checks of its context
+const contextCheckInterval = 1024
@@ -0,0 +1,92 @@
+package maze
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// countdownContext is a context which is cancelled once its error has been
+// checked a given number of times, so that generators stop partway through
+type countdownContext struct {
+	context.Context
+	remaining int
+}
+
+func (c *countdownContext) Err() error {
+	if c.remaining <= 0 {
+		return context.Canceled
+	}
+	c.remaining--
+	return nil
+}
+
+// ctxGenerators are the generators which can be cancelled through a context
+var ctxGenerators = []struct {
+	name     string
+	generate func(context.Context, *Grid) error
+}{
+	{"AldousBroder", AldousBroderCtx},
+	{"RecursiveBacktracker", RecursiveBacktrackerCtx},
+	{"Wilsons", WilsonsCtx},
+}
+
+func TestCtxGeneratorsStopWhenCancelled(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	contexts := []struct {
+		name    string
+		ctx     func() context.Context
+		partway bool
+	}{
+		{"AlreadyCancelled", func() context.Context { return cancelled }, false},
+		{"CancelledPartway", func() context.Context { return &countdownContext{context.Background(), 2} }, true},
+	}
+	for _, gen := range ctxGenerators {
+		for _, c := range contexts {
+			t.Run(gen.name+"/"+c.name, func(t *testing.T) {
+				g := NewGrid(64, 64)
+				if err := gen.generate(c.ctx(), &g); !errors.Is(err, context.Canceled) {
+					t.Fatalf("returned %v, want %v", err, context.Canceled)
+				}
+				carved := 0
+				for _, cell := range g.Cells() {
+					for _, l := range cell.Links() {
+						if !l.Linked(cell) {
+							t.Fatalf("cell [%d, %d] is linked one way to [%d, %d]", cell.Row, cell.Column, l.Row, l.Column)
+						}
+					}
+					if cell.hasLinks() {
+						carved++
+					}
+				}
+				if cycles := Cycles(&g); len(cycles) != 0 {
+					t.Errorf("partial maze has %d loops", len(cycles))
+				}
+				if !c.partway && carved != 0 {
+					t.Errorf("carved %d cells before checking the context", carved)
+				}
+				// The first walk of Wilsons may not have reached the maze yet, so
+				// only the upper bound is known.  Two checks allow too few steps
+				// to finish a maze of this size
+				if c.partway && (carved == int(g.Size()) || IsPerfect(&g)) {
+					t.Errorf("carved %d of %d cells, want a partial maze", carved, g.Size())
+				}
+			})
+		}
+	}
+}
+
+func TestCtxGeneratorsComplete(t *testing.T) {
+	for _, gen := range ctxGenerators {
+		t.Run(gen.name, func(t *testing.T) {
+			g := NewGrid(16, 16)
+			if err := gen.generate(context.Background(), &g); err != nil {
+				t.Fatal(err)
+			}
+			if !IsPerfect(&g) {
+				t.Errorf("maze is not perfect:\n%s", g.ToString())
+			}
+		})
+	}
+}
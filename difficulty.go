@@ -0,0 +1,176 @@
+package maze
+
+const (
+	// misdirectionThreshold is the number of cells a side branch must exceed
+	// before it counts as a tempting wrong turn
+	misdirectionThreshold = 3
+)
+
+// branch describes a part of the maze hanging off the solution path
+type branch struct {
+	// The cell on the solution path where the branch begins
+	junction *Cell
+	// The number of cells in the branch
+	size int
+	// The number of steps from the junction to the farthest cell in the branch
+	depth int
+}
+
+// solutionBranches returns every branch which leaves the given solution path.
+// A branch contains all of the cells reachable from one of the junction's links
+// without stepping back onto the path
+func solutionBranches(path []*Cell) []branch {
+	onPath := map[*Cell]bool{}
+	for _, cell := range path {
+		onPath[cell] = true
+	}
+
+	branches := []branch{}
+	for _, junction := range path {
+		for _, entry := range junction.Links() {
+			if onPath[entry] {
+				continue
+			}
+			depth := map[*Cell]int{entry: 1}
+			frontier := []*Cell{entry}
+			deepest := 1
+			for len(frontier) > 0 {
+				cell := frontier[0]
+				frontier = frontier[1:]
+				for _, n := range cell.Links() {
+					if _, ok := depth[n]; !ok && !onPath[n] {
+						depth[n] = depth[cell] + 1
+						if depth[n] > deepest {
+							deepest = depth[n]
+						}
+						frontier = append(frontier, n)
+					}
+				}
+			}
+			branches = append(branches, branch{junction: junction, size: len(depth), depth: deepest})
+		}
+	}
+	return branches
+}
+
+// Misdirections returns the number of cells on the solution from start to goal
+// where the player is offered a tempting wrong turn: a side branch containing
+// more than a handful of cells.  Long decoy corridors make a maze harder than
+// the length of its solution suggests.  If the goal can't be reached, the
+// result is 0
+func Misdirections(g *Grid, start, goal *Cell) int {
+	path, _ := ShortestPath(start, goal)
+	junctions := map[*Cell]bool{}
+	for _, b := range solutionBranches(path) {
+		if b.size > misdirectionThreshold {
+			junctions[b.junction] = true
+		}
+	}
+	return len(junctions)
+}
+
+// TrapPotential scores every cell by how far out of their way a player who
+// wanders into it must travel: the length of the shortest route from start to
+// goal passing through the cell, minus the length of the solution.  Cells on the
+// solution score 0, and cells deep within long dead ends score highest.  Cells
+// which aren't connected to both start and goal are omitted
+func TrapPotential(g *Grid, start, goal *Cell) map[*Cell]float64 {
+	fromStart := ComputeDistances(start)
+	fromGoal := ComputeDistances(goal)
+	solution, ok := fromStart.Get(goal)
+	potential := map[*Cell]float64{}
+	if !ok {
+		return potential
+	}
+
+	for _, cell := range g.Cells() {
+		toCell, ok1 := fromStart.Get(cell)
+		toGoal, ok2 := fromGoal.Get(cell)
+		if ok1 && ok2 {
+			potential[cell] = float64(toCell + toGoal - solution)
+		}
+	}
+	return potential
+}
+
+// LongestDecoy finds the most convincing wrong turn on the solution from start
+// to goal: the cell on the solution where the deepest side branch begins, along
+// with the number of steps from that cell to the far end of the branch.  If the
+// solution has no side branches, or the goal can't be reached, it returns nil
+// and 0
+func LongestDecoy(g *Grid, start, goal *Cell) (branchStart *Cell, length int) {
+	path, _ := ShortestPath(start, goal)
+	for _, b := range solutionBranches(path) {
+		if b.depth > length {
+			branchStart, length = b.junction, b.depth
+		}
+	}
+	return branchStart, length
+}
+
+// SolutionBorderFraction returns the fraction of the cells on the solution from
+// start to goal which lie along the outer edge of the grid.  Solutions which hug
+// the border tend to be less interesting.  If the goal can't be reached, the
+// result is 0
+func SolutionBorderFraction(g *Grid, start, goal *Cell) float64 {
+	path, ok := ShortestPath(start, goal)
+	if !ok {
+		return 0
+	}
+	border := 0
+	for _, cell := range path {
+		if g.onBorder(cell) {
+			border++
+		}
+	}
+	return float64(border) / float64(len(path))
+}
+
+// SolutionBranchiness returns the average number of passages leading out of the
+// cells on the solution from start to goal.  A solution running through many
+// junctions offers more wrong turns than one running through plain corridors.
+// If the goal can't be reached, the result is 0
+func SolutionBranchiness(g *Grid, start, goal *Cell) float64 {
+	path, ok := ShortestPath(start, goal)
+	if !ok {
+		return 0
+	}
+	links := 0
+	for _, cell := range path {
+		links += cell.linkCount()
+	}
+	return float64(links) / float64(len(path))
+}
+
+// FogDifficulty estimates how hard the maze is to solve for a player who can
+// only see cells within sightRadius steps of where they stand.  At each cell on
+// the solution from start to goal, a side branch is blind if its far end lies
+// beyond the player's sight, so the player can't rule it out.  Unless the goal
+// is already in sight, a player choosing at random among the correct way and n
+// blind branches guesses wrong with probability n / (n + 1); the difficulty is
+// the sum of these probabilities along the solution.  If the goal can't be
+// reached, the result is 0
+func FogDifficulty(g *Grid, start, goal *Cell, sightRadius int) float64 {
+	path, ok := ShortestPath(start, goal)
+	if !ok {
+		return 0
+	}
+	remaining := map[*Cell]int{}
+	for i, cell := range path {
+		remaining[cell] = len(path) - 1 - i
+	}
+
+	blind := map[*Cell]int{}
+	for _, b := range solutionBranches(path) {
+		if b.depth > sightRadius && remaining[b.junction] > sightRadius {
+			blind[b.junction]++
+		}
+	}
+
+	difficulty := 0.0
+	for _, cell := range path {
+		n := blind[cell]
+		difficulty += float64(n) / float64(n+1)
+	}
+	return difficulty
+}
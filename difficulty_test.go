@@ -0,0 +1,180 @@
+package maze
+
+import (
+	"reflect"
+	"testing"
+)
+
+// comb returns a maze whose solution runs along the top row from the first cell
+// to the last, with side branches hanging below it:
+//
+//	a corridor three cells deep below [0, 1]
+//	four cells winding four steps deep below [0, 2]
+//	a single cell below [0, 3]
+//
+// The cells of the first column and the last column below the top row aren't
+// linked to anything
+func comb() *Grid {
+	return linkedGrid(4, 5, [][4]int64{
+		{0, 0, 0, 1}, {0, 1, 0, 2}, {0, 2, 0, 3}, {0, 3, 0, 4},
+		{0, 1, 1, 1}, {1, 1, 2, 1}, {2, 1, 3, 1},
+		{0, 2, 1, 2}, {1, 2, 2, 2}, {2, 2, 2, 3}, {2, 3, 3, 3},
+		{0, 3, 1, 3},
+	})
+}
+
+func TestMisdirections(t *testing.T) {
+	tests := []struct {
+		name        string
+		start, goal [2]int64
+		want        int
+	}{
+		{"AlongTheTop", [2]int64{0, 0}, [2]int64{0, 4}, 1},
+		{"FromTheFirstBranch", [2]int64{3, 1}, [2]int64{0, 4}, 1},
+		{"BetweenBranches", [2]int64{3, 3}, [2]int64{3, 1}, 0},
+		{"SameCell", [2]int64{0, 2}, [2]int64{0, 2}, 1},
+		{"Unreachable", [2]int64{0, 0}, [2]int64{1, 0}, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := comb()
+			if got := Misdirections(g, g.At(tc.start[0], tc.start[1]), g.At(tc.goal[0], tc.goal[1])); got != tc.want {
+				t.Errorf("Misdirections() = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTrapPotential(t *testing.T) {
+	tests := []struct {
+		name        string
+		start, goal [2]int64
+		want        map[[2]int64]float64
+	}{
+		{"AlongTheTop", [2]int64{0, 0}, [2]int64{0, 4}, map[[2]int64]float64{
+			{0, 0}: 0, {0, 1}: 0, {0, 2}: 0, {0, 3}: 0, {0, 4}: 0,
+			{1, 1}: 2, {2, 1}: 4, {3, 1}: 6,
+			{1, 2}: 2, {2, 2}: 4, {2, 3}: 6, {3, 3}: 8,
+			{1, 3}: 2,
+		}},
+		{"SameCell", [2]int64{0, 3}, [2]int64{0, 3}, map[[2]int64]float64{
+			{0, 0}: 6, {0, 1}: 4, {0, 2}: 2, {0, 3}: 0, {0, 4}: 2,
+			{1, 1}: 6, {2, 1}: 8, {3, 1}: 10,
+			{1, 2}: 4, {2, 2}: 6, {2, 3}: 8, {3, 3}: 10,
+			{1, 3}: 2,
+		}},
+		{"Unreachable", [2]int64{0, 0}, [2]int64{1, 0}, map[[2]int64]float64{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := comb()
+			got := map[[2]int64]float64{}
+			for cell, p := range TrapPotential(g, g.At(tc.start[0], tc.start[1]), g.At(tc.goal[0], tc.goal[1])) {
+				got[[2]int64{cell.Row, cell.Column}] = p
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("TrapPotential() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLongestDecoy(t *testing.T) {
+	none := [2]int64{-1, -1}
+	tests := []struct {
+		name        string
+		grid        *Grid
+		start, goal [2]int64
+		junction    [2]int64
+		length      int
+	}{
+		{"AlongTheTop", comb(), [2]int64{0, 0}, [2]int64{0, 4}, [2]int64{0, 2}, 4},
+		{"FromTheDeepestBranch", comb(), [2]int64{3, 3}, [2]int64{0, 4}, [2]int64{0, 2}, 4},
+		{"PastAShorterBranch", comb(), [2]int64{0, 1}, [2]int64{0, 4}, [2]int64{0, 2}, 4},
+		{"OnlyShortBranches", comb(), [2]int64{3, 3}, [2]int64{0, 0}, [2]int64{0, 1}, 3},
+		{"NoBranches", serpentine(3, 3), [2]int64{0, 0}, [2]int64{2, 2}, none, 0},
+		{"Unreachable", comb(), [2]int64{0, 0}, [2]int64{1, 0}, none, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := tc.grid
+			junction, length := LongestDecoy(g, g.At(tc.start[0], tc.start[1]), g.At(tc.goal[0], tc.goal[1]))
+			if junction != g.At(tc.junction[0], tc.junction[1]) || length != tc.length {
+				t.Errorf("LongestDecoy() = %v, %d, want %v, %d", junction, length, tc.junction, tc.length)
+			}
+		})
+	}
+}
+
+func TestSolutionBorderFraction(t *testing.T) {
+	tests := []struct {
+		name        string
+		grid        *Grid
+		start, goal [2]int64
+		want        float64
+	}{
+		{"AlongTheTop", comb(), [2]int64{0, 0}, [2]int64{0, 4}, 1},
+		{"ThroughTheMiddle", comb(), [2]int64{3, 1}, [2]int64{0, 0}, 0.6},
+		{"InsideOnly", comb(), [2]int64{1, 2}, [2]int64{2, 2}, 0},
+		{"Unreachable", comb(), [2]int64{0, 0}, [2]int64{1, 0}, 0},
+		{"Serpentine", serpentine(3, 3), [2]int64{0, 0}, [2]int64{2, 2}, 8.0 / 9},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := tc.grid
+			if got := SolutionBorderFraction(g, g.At(tc.start[0], tc.start[1]), g.At(tc.goal[0], tc.goal[1])); got != tc.want {
+				t.Errorf("SolutionBorderFraction() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSolutionBranchiness(t *testing.T) {
+	tests := []struct {
+		name        string
+		grid        *Grid
+		start, goal [2]int64
+		want        float64
+	}{
+		{"AlongTheTop", comb(), [2]int64{0, 0}, [2]int64{0, 4}, 11.0 / 5},
+		{"DownABranch", comb(), [2]int64{0, 1}, [2]int64{3, 1}, 2},
+		{"SameCell", comb(), [2]int64{0, 2}, [2]int64{0, 2}, 3},
+		{"Corridor", serpentine(3, 3), [2]int64{0, 0}, [2]int64{2, 2}, 16.0 / 9},
+		{"Unreachable", comb(), [2]int64{0, 0}, [2]int64{1, 0}, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := tc.grid
+			if got := SolutionBranchiness(g, g.At(tc.start[0], tc.start[1]), g.At(tc.goal[0], tc.goal[1])); got != tc.want {
+				t.Errorf("SolutionBranchiness() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFogDifficulty(t *testing.T) {
+	tests := []struct {
+		name        string
+		grid        *Grid
+		start, goal [2]int64
+		sightRadius int
+		want        float64
+	}{
+		{"Blind", comb(), [2]int64{0, 0}, [2]int64{0, 4}, 0, 1.5},
+		{"SeesTheShortBranch", comb(), [2]int64{0, 0}, [2]int64{0, 4}, 1, 1},
+		{"SeesTheGoal", comb(), [2]int64{0, 0}, [2]int64{0, 4}, 2, 0.5},
+		{"SeesEverything", comb(), [2]int64{0, 0}, [2]int64{0, 4}, 3, 0},
+		// Both deep branches leave the start, and the short one is in sight
+		{"TwoBranchesAtOnce", comb(), [2]int64{0, 2}, [2]int64{0, 4}, 1, 2.0 / 3},
+		{"Corridor", serpentine(3, 3), [2]int64{0, 0}, [2]int64{2, 2}, 0, 0},
+		{"Unreachable", comb(), [2]int64{0, 0}, [2]int64{1, 0}, 0, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := tc.grid
+			if got := FogDifficulty(g, g.At(tc.start[0], tc.start[1]), g.At(tc.goal[0], tc.goal[1]), tc.sightRadius); got != tc.want {
+				t.Errorf("FogDifficulty() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
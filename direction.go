@@ -0,0 +1,66 @@
+package maze
+
+// Direction identifies one of the sides of a cell in a rectangular grid
+type Direction int
+
+// The directions a cell's neighbors can lie in
+const (
+	North Direction = iota
+	South
+	East
+	West
+)
+
+// String returns the name of the direction
+func (d Direction) String() string {
+	switch d {
+	case North:
+		return "North"
+	case South:
+		return "South"
+	case East:
+		return "East"
+	case West:
+		return "West"
+	}
+	return "Unknown"
+}
+
+// neighbor returns the neighbor of a cell in the given direction
+func (c *Cell) neighbor(d Direction) *Cell {
+	switch d {
+	case North:
+		return c.North
+	case South:
+		return c.South
+	case East:
+		return c.East
+	case West:
+		return c.West
+	}
+	return nil
+}
+
+// clockwise returns the direction a quarter turn clockwise from this one
+func (d Direction) clockwise() Direction {
+	switch d {
+	case North:
+		return East
+	case East:
+		return South
+	case South:
+		return West
+	}
+	return North
+}
+
+// counterClockwise returns the direction a quarter turn counter-clockwise from
+// this one
+func (d Direction) counterClockwise() Direction {
+	return d.clockwise().clockwise().clockwise()
+}
+
+// opposite returns the direction facing away from this one
+func (d Direction) opposite() Direction {
+	return d.clockwise().clockwise()
+}
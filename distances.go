@@ -0,0 +1,156 @@
+package maze
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// Distances records the number of steps from a root cell to every cell which
+// can be reached from it
+type Distances struct {
+	// The cell distances are measured from
+	root *Cell
+	// The distance to each reachable cell
+	cells map[*Cell]int64
+	// The reachable cells, in the order they were found
+	order []*Cell
+}
+
+// ComputeDistances measures the distance from root to every cell it can reach
+// by following links, using a breadth-first search
+func ComputeDistances(root *Cell) *Distances {
+	d := &Distances{
+		root:  root,
+		cells: map[*Cell]int64{root: 0},
+		order: []*Cell{root}}
+	for i := 0; i < len(d.order); i++ {
+		cell := d.order[i]
+		for _, n := range cell.Links() {
+			if _, ok := d.cells[n]; !ok {
+				d.cells[n] = d.cells[cell] + 1
+				d.order = append(d.order, n)
+			}
+		}
+	}
+	return d
+}
+
+// Get returns the distance from the root to a cell, and false if the cell
+// can't be reached
+func (d *Distances) Get(c *Cell) (int64, bool) {
+	dist, ok := d.cells[c]
+	return dist, ok
+}
+
+// Max returns the cell farthest from the root along with its distance
+func (d *Distances) Max() (*Cell, int64) {
+	// Cells are found in order of increasing distance
+	farthest := d.order[len(d.order)-1]
+	return farthest, d.cells[farthest]
+}
+
+// DistanceField returns the number of steps from root to every cell of the grid
+// as a Rows x Columns array.  Cells which can't be reached from root, including
+// cells disabled by the mask, have a distance of -1
+func (g *Grid) DistanceField(root *Cell) [][]int64 {
+	field := make([][]int64, g.Rows)
+	for r := range field {
+		field[r] = make([]int64, g.Columns)
+		for c := range field[r] {
+			field[r][c] = -1
+		}
+	}
+	if root == nil || g.At(root.Row, root.Column) != root {
+		return field
+	}
+
+	// Cells which are linked to the grid without being part of it, such as those
+	// running beneath a weave, have no place in the field
+	offGrid := map[*Cell]int64{}
+	distance := func(c *Cell) (int64, bool) {
+		if g.At(c.Row, c.Column) == c {
+			d := field[c.Row][c.Column]
+			return d, d >= 0
+		}
+		d, ok := offGrid[c]
+		return d, ok
+	}
+	field[root.Row][root.Column] = 0
+	frontier := []*Cell{root}
+	for len(frontier) > 0 {
+		cell := frontier[0]
+		frontier = frontier[1:]
+		d, _ := distance(cell)
+		for _, n := range cell.Links() {
+			if _, ok := distance(n); ok {
+				continue
+			}
+			if g.At(n.Row, n.Column) == n {
+				field[n.Row][n.Column] = d + 1
+			} else {
+				offGrid[n] = d + 1
+			}
+			frontier = append(frontier, n)
+		}
+	}
+	return field
+}
+
+// DistancesToCSV writes the distance from root to every cell of the grid as
+// comma-separated values, with one record for each row of the grid.  Cells
+// which can't be reached from root have a distance of -1
+func (g *Grid) DistancesToCSV(w io.Writer, root *Cell) error {
+	out := csv.NewWriter(w)
+	for _, row := range g.DistanceField(root) {
+		record := make([]string, len(row))
+		for c, d := range row {
+			record[c] = strconv.FormatInt(d, 10)
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// distanceShading is the sequence of glyphs used to shade cells, from nearest to
+// farthest
+const distanceShading = ".:-=+*#%@"
+
+// ToStringWithDistances creates a textual representation of the maze in which
+// each cell is shaded according to its distance from the root, from "." for the
+// root to "@" for the farthest cell.  Cells which can't be reached are left
+// blank
+func (g *Grid) ToStringWithDistances(d *Distances) string {
+	shades := []rune(distanceShading)
+	_, max := d.Max()
+	return g.toString(LightWalls, 3, 1, func(cell *Cell) rune {
+		dist, ok := d.Get(cell)
+		if !ok {
+			return ' '
+		}
+		if max == 0 {
+			return shades[0]
+		}
+		return shades[dist*int64(len(shades)-1)/max]
+	})
+}
+
+// Equidistant returns the cells, in row-major order, which are the same number
+// of steps from a as from b.  These are the natural meeting points for two
+// players starting at a and b
+func Equidistant(g *Grid, a, b *Cell) []*Cell {
+	fromA := ComputeDistances(a)
+	fromB := ComputeDistances(b)
+	cells := []*Cell{}
+	for _, cell := range g.Cells() {
+		distA, okA := fromA.Get(cell)
+		distB, okB := fromB.Get(cell)
+		if okA && okB && distA == distB {
+			cells = append(cells, cell)
+		}
+	}
+	return cells
+}
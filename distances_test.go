@@ -0,0 +1,193 @@
+package maze
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// serpentine returns a maze with a single path which runs east along the first
+// row, west along the second, and so on
+func serpentine(rows, columns int64) *Grid {
+	g := NewGrid(rows, columns)
+	for r := int64(0); r < rows; r++ {
+		for c := int64(0); c+1 < columns; c++ {
+			g.At(r, c).Link(g.At(r, c+1))
+		}
+		if r+1 < rows {
+			end := columns - 1
+			if r%2 == 1 {
+				end = 0
+			}
+			g.At(r, end).Link(g.At(r+1, end))
+		}
+	}
+	return &g
+}
+
+// linkedGrid returns a grid in which the given pairs of cells are linked
+func linkedGrid(rows, columns int64, links [][4]int64) *Grid {
+	g := NewGrid(rows, columns)
+	for _, l := range links {
+		g.At(l[0], l[1]).Link(g.At(l[2], l[3]))
+	}
+	return &g
+}
+
+func TestComputeDistances(t *testing.T) {
+	tests := []struct {
+		name string
+		grid *Grid
+		root [2]int64
+		want [][]int64
+		max  int64
+	}{
+		{"SingleCell", linkedGrid(1, 1, nil), [2]int64{0, 0}, [][]int64{{0}}, 0},
+		{"Serpentine", serpentine(3, 3), [2]int64{0, 0}, [][]int64{{0, 1, 2}, {5, 4, 3}, {6, 7, 8}}, 8},
+		{"SerpentineMiddle", serpentine(3, 3), [2]int64{1, 1}, [][]int64{{4, 3, 2}, {1, 0, 1}, {2, 3, 4}}, 4},
+		{"Unreachable", linkedGrid(2, 2, [][4]int64{{0, 0, 0, 1}, {0, 1, 1, 1}}), [2]int64{0, 0}, [][]int64{{0, 1}, {-1, 2}}, 2},
+		{"Loop", linkedGrid(2, 2, [][4]int64{{0, 0, 0, 1}, {0, 1, 1, 1}, {1, 1, 1, 0}, {1, 0, 0, 0}}), [2]int64{0, 0}, [][]int64{{0, 1}, {1, 2}}, 2},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d := ComputeDistances(tc.grid.At(tc.root[0], tc.root[1]))
+			for r, row := range tc.want {
+				for c, want := range row {
+					got, ok := d.Get(tc.grid.At(int64(r), int64(c)))
+					if want < 0 && ok {
+						t.Errorf("Get([%d, %d]) = %d, want unreachable", r, c, got)
+					}
+					if want >= 0 && (!ok || got != want) {
+						t.Errorf("Get([%d, %d]) = %d, %v, want %d", r, c, got, ok, want)
+					}
+				}
+			}
+			if cell, max := d.Max(); max != tc.max || tc.want[cell.Row][cell.Column] != max {
+				t.Errorf("Max() = [%d, %d], %d, want distance %d", cell.Row, cell.Column, max, tc.max)
+			}
+			if field := tc.grid.DistanceField(tc.grid.At(tc.root[0], tc.root[1])); !reflect.DeepEqual(field, tc.want) {
+				t.Errorf("DistanceField() = %v, want %v", field, tc.want)
+			}
+		})
+	}
+}
+
+func TestDistanceFieldMasked(t *testing.T) {
+	g := NewMaskedGrid(maskFromString(t, "..\nX."))
+	g.At(0, 0).Link(g.At(0, 1))
+	g.At(0, 1).Link(g.At(1, 1))
+	want := [][]int64{{0, 1}, {-1, 2}}
+	if field := g.DistanceField(g.At(0, 0)); !reflect.DeepEqual(field, want) {
+		t.Errorf("DistanceField() = %v, want %v", field, want)
+	}
+	want = [][]int64{{-1, -1}, {-1, -1}}
+	if field := g.DistanceField(nil); !reflect.DeepEqual(field, want) {
+		t.Errorf("DistanceField(nil) = %v, want %v", field, want)
+	}
+}
+
+func TestDistanceFieldMatchesComputeDistances(t *testing.T) {
+	braided := func(seed int64) *Grid {
+		r := rand.New(rand.NewSource(seed))
+		g := NewGrid(12, 12)
+		RecursiveBacktrackerRand(&g, r)
+		Braid(&g, 0.5, r)
+		return &g
+	}
+	woven := func(seed int64) *Grid {
+		g := NewWeaveGrid(12, 12)
+		WeaveBacktrackerRand(g, rand.New(rand.NewSource(seed)))
+		if len(g.UnderCells()) == 0 {
+			t.Fatalf("seed %d wove no tunnels", seed)
+		}
+		return &g.Grid
+	}
+	for seed := int64(1); seed <= 5; seed++ {
+		for name, g := range map[string]*Grid{"Braided": braided(seed), "Woven": woven(seed)} {
+			for _, root := range []*Cell{g.At(0, 0), g.At(5, 7), g.At(11, 11)} {
+				field := g.DistanceField(root)
+				d := ComputeDistances(root)
+				for _, cell := range g.Cells() {
+					want, ok := d.Get(cell)
+					if !ok {
+						want = -1
+					}
+					if got := field[cell.Row][cell.Column]; got != want {
+						t.Errorf("%s seed %d: DistanceField([%d, %d])[%d][%d] = %d, want %d", name, seed, root.Row, root.Column, cell.Row, cell.Column, got, want)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestDistancesToCSV(t *testing.T) {
+	g := linkedGrid(2, 2, [][4]int64{{0, 0, 0, 1}, {0, 1, 1, 1}})
+	var out bytes.Buffer
+	if err := g.DistancesToCSV(&out, g.At(0, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if want := "0,1\n-1,2\n"; out.String() != want {
+		t.Errorf("DistancesToCSV() = %q, want %q", out.String(), want)
+	}
+}
+
+func TestEquidistant(t *testing.T) {
+	tests := []struct {
+		name string
+		grid *Grid
+		a, b [2]int64
+		want [][2]int64
+	}{
+		{"SameCell", serpentine(1, 3), [2]int64{0, 0}, [2]int64{0, 0}, [][2]int64{{0, 0}, {0, 1}, {0, 2}}},
+		{"Ends", serpentine(1, 3), [2]int64{0, 0}, [2]int64{0, 2}, [][2]int64{{0, 1}}},
+		{"OddGap", serpentine(1, 4), [2]int64{0, 0}, [2]int64{0, 3}, [][2]int64{}},
+		{"Branch", linkedGrid(2, 3, [][4]int64{{0, 0, 0, 1}, {0, 1, 0, 2}, {0, 1, 1, 1}}), [2]int64{0, 0}, [2]int64{0, 2}, [][2]int64{{0, 1}, {1, 1}}},
+		{"Disconnected", linkedGrid(1, 3, [][4]int64{{0, 0, 0, 1}}), [2]int64{0, 0}, [2]int64{0, 2}, [][2]int64{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := [][2]int64{}
+			for _, c := range Equidistant(tc.grid, tc.grid.At(tc.a[0], tc.a[1]), tc.grid.At(tc.b[0], tc.b[1])) {
+				got = append(got, [2]int64{c.Row, c.Column})
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Equidistant() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestToStringWithDistances(t *testing.T) {
+	// A hook from the top left corner, leaving the bottom left cell unreachable
+	g := linkedGrid(2, 3, [][4]int64{{0, 0, 0, 1}, {0, 1, 0, 2}, {0, 2, 1, 2}, {1, 2, 1, 1}})
+	tests := []struct {
+		name string
+		root [2]int64
+		want string
+	}{
+		{"Corner", [2]int64{0, 0}, "┌───────────┐   \n" +
+			"│ .   -   + │   \n" +
+			"├───┬────   │   \n" +
+			"│   │ @   # │   \n" +
+			"└───┴───────┘   \n"},
+		{"Middle", [2]int64{0, 2}, "┌───────────┐   \n" +
+			"│ @   +   . │   \n" +
+			"├───┬────   │   \n" +
+			"│   │ @   + │   \n" +
+			"└───┴───────┘   \n"},
+		{"Unreachable", [2]int64{1, 0}, "┌───────────┐   \n" +
+			"│           │   \n" +
+			"├───┬────   │   \n" +
+			"│ . │       │   \n" +
+			"└───┴───────┘   \n"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := g.ToStringWithDistances(ComputeDistances(g.At(tc.root[0], tc.root[1]))); got != tc.want {
+				t.Errorf("ToStringWithDistances() =\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
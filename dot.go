@@ -0,0 +1,43 @@
+package maze
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// dotSpacing is the distance in points between neighboring cells in a DOT graph
+const dotSpacing = 72
+
+// dotName returns the name of the node representing a cell in a DOT graph
+func dotName(c *Cell) string {
+	return fmt.Sprintf("r%dc%d", c.Row, c.Column)
+}
+
+// ToDOT writes the maze as an undirected Graphviz graph in which each cell is a
+// node named r{row}c{column} and each link between two cells is an edge.  Nodes
+// are given positions on a grid, so the output can be laid out with neato -n.
+// Nodes and edges are written in row-major order, so the output is stable for a
+// given maze
+func (g *Grid) ToDOT(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("graph maze {\n")
+	sb.WriteString("\tnode [shape=point];\n")
+	cells := g.Cells()
+	for _, cell := range cells {
+		fmt.Fprintf(&sb, "\t%s [pos=\"%d,%d\"];\n", dotName(cell),
+			cell.Column*dotSpacing, (g.Rows-1-cell.Row)*dotSpacing)
+	}
+	for _, cell := range cells {
+		for _, l := range cell.Links() {
+			// Write each edge once, from the earlier of its cells
+			later := l.Row > cell.Row || (l.Row == cell.Row && l.Column > cell.Column)
+			if later && l.Linked(cell) && g.At(l.Row, l.Column) == l {
+				fmt.Fprintf(&sb, "\t%s -- %s;\n", dotName(cell), dotName(l))
+			}
+		}
+	}
+	sb.WriteString("}\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
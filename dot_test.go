@@ -0,0 +1,71 @@
+package maze
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestToDOT(t *testing.T) {
+	masked := NewMaskedGrid(maskFromString(t, ".X\n.."))
+	masked.At(0, 0).Link(masked.At(1, 0))
+	masked.At(1, 0).Link(masked.At(1, 1))
+	tests := []struct {
+		name string
+		grid *Grid
+		want string
+	}{
+		{"Empty", linkedGrid(0, 0, nil), "graph maze {\n\tnode [shape=point];\n}\n"},
+		{"Unlinked", linkedGrid(1, 2, nil), "graph maze {\n" +
+			"\tnode [shape=point];\n" +
+			"\tr0c0 [pos=\"0,0\"];\n" +
+			"\tr0c1 [pos=\"72,0\"];\n" +
+			"}\n"},
+		{"Serpentine", serpentine(2, 2), "graph maze {\n" +
+			"\tnode [shape=point];\n" +
+			"\tr0c0 [pos=\"0,72\"];\n" +
+			"\tr0c1 [pos=\"72,72\"];\n" +
+			"\tr1c0 [pos=\"0,0\"];\n" +
+			"\tr1c1 [pos=\"72,0\"];\n" +
+			"\tr0c0 -- r0c1;\n" +
+			"\tr0c1 -- r1c1;\n" +
+			"\tr1c0 -- r1c1;\n" +
+			"}\n"},
+		{"Masked", masked, "graph maze {\n" +
+			"\tnode [shape=point];\n" +
+			"\tr0c0 [pos=\"0,72\"];\n" +
+			"\tr1c0 [pos=\"0,0\"];\n" +
+			"\tr1c1 [pos=\"72,0\"];\n" +
+			"\tr0c0 -- r1c0;\n" +
+			"\tr1c0 -- r1c1;\n" +
+			"}\n"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := tc.grid.ToDOT(&out); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tc.want {
+				t.Errorf("ToDOT() =\n%s\nwant:\n%s", out.String(), tc.want)
+			}
+		})
+	}
+}
+
+func TestToDOTEdgeCount(t *testing.T) {
+	// Each passage of a perfect maze is written exactly once
+	g := NewGrid(7, 9)
+	RecursiveBacktrackerRand(&g, rand.New(rand.NewSource(1)))
+	var out bytes.Buffer
+	if err := g.ToDOT(&out); err != nil {
+		t.Fatal(err)
+	}
+	if nodes := strings.Count(out.String(), "[pos="); int64(nodes) != g.Size() {
+		t.Errorf("ToDOT() wrote %d nodes, want %d", nodes, g.Size())
+	}
+	if edges := strings.Count(out.String(), " -- "); int64(edges) != g.Size()-1 {
+		t.Errorf("ToDOT() wrote %d edges, want %d", edges, g.Size()-1)
+	}
+}
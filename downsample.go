@@ -0,0 +1,29 @@
+package maze
+
+import (
+	"fmt"
+)
+
+// Downsample produces a coarser version of the maze in which every
+// factor x factor block of cells becomes a single cell.  Two blocks are linked
+// if any passage crosses the boundary between them.  Blocks along the bottom
+// and right edges may be smaller when the grid doesn't divide evenly.  An error
+// is returned if factor is less than one
+func Downsample(g *Grid, factor int64) (*Grid, error) {
+	if factor < 1 {
+		return nil, fmt.Errorf("invalid downsampling factor: %d", factor)
+	}
+
+	coarse := NewGrid((g.Rows+factor-1)/factor, (g.Columns+factor-1)/factor)
+	ForEachAdjacentPair(g, func(a, b *Cell) {
+		if !a.Linked(b) {
+			return
+		}
+		blockA := coarse.At(a.Row/factor, a.Column/factor)
+		blockB := coarse.At(b.Row/factor, b.Column/factor)
+		if blockA != blockB {
+			blockA.Link(blockB)
+		}
+	})
+	return &coarse, nil
+}
@@ -0,0 +1,40 @@
+package maze
+
+import (
+	"testing"
+)
+
+func TestDownsample(t *testing.T) {
+	tests := []struct {
+		name   string
+		grid   *Grid
+		factor int64
+		want   *Grid
+	}{
+		{"Identity", serpentine(3, 3), 1, serpentine(3, 3)},
+		{"Halved", serpentine(4, 4), 2, linkedGrid(2, 2, [][4]int64{{0, 0, 0, 1}, {0, 0, 1, 0}, {1, 0, 1, 1}})},
+		{"Uneven", serpentine(3, 3), 2, linkedGrid(2, 2, [][4]int64{{0, 0, 0, 1}, {0, 0, 1, 0}, {1, 0, 1, 1}})},
+		{"Unlinked", linkedGrid(4, 4, nil), 2, linkedGrid(2, 2, nil)},
+		{"SingleBlock", serpentine(3, 3), 3, linkedGrid(1, 1, nil)},
+		{"LargerThanGrid", serpentine(2, 3), 5, linkedGrid(1, 1, nil)},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Downsample(tc.grid, tc.factor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !Equal(got, tc.want) {
+				t.Errorf("Downsample() =\n%s\nwant:\n%s", got.ToString(), tc.want.ToString())
+			}
+		})
+	}
+}
+
+func TestDownsampleInvalidFactor(t *testing.T) {
+	for _, factor := range []int64{0, -1} {
+		if g, err := Downsample(serpentine(2, 2), factor); err == nil || g != nil {
+			t.Errorf("Downsample(%d) = %v, %v, want an error", factor, g, err)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package maze
+
+import (
+	"math/rand"
+)
+
+// Ellers uses Eller's maze creation algorithm to create a maze in a rectangular
+// grid.  It works through the grid one row at a time, tracking which cells of
+// the current row are already connected to each other.  Neighboring cells in
+// different sets are randomly linked, and then every set is carried into the
+// next row through at least one downward passage.  The final row links every
+// remaining set together.  Only a single row of state is needed at any time.
+// On a masked grid, sets which cannot continue downward are left unjoined, so
+// the result may be disconnected
+func Ellers(g *Grid) {
+	EllersRand(g, defaultRand)
+}
+
+// EllersRand is Ellers using the provided random source
+func EllersRand(g *Grid, r *rand.Rand) {
+	sets := make([]int, g.Columns)
+	for i := range sets {
+		sets[i] = -1
+	}
+	next := 0
+	for rowIndex, row := range g.grid {
+		// Cells not reached from above begin in sets of their own
+		for i := range sets {
+			if sets[i] < 0 {
+				sets[i] = next
+				next++
+			}
+		}
+		last := rowIndex == len(g.grid)-1
+		ellersMergeRow(row, sets, last, r)
+		if !last {
+			sets = ellersCarveDown(row, sets, r)
+		}
+	}
+}
+
+// ellersMergeRow randomly links neighboring cells in a row which belong to
+// different sets, merging their sets.  If final is set, every such pair is
+// linked so that the whole row ends up in a single set
+func ellersMergeRow(row []*Cell, sets []int, final bool, r *rand.Rand) {
+	for c := 0; c+1 < len(row); c++ {
+		if row[c] == nil || row[c+1] == nil || sets[c] == sets[c+1] || (!final && r.Intn(2) == 0) {
+			continue
+		}
+		row[c].Link(row[c+1])
+		absorbed := sets[c+1]
+		for i := range sets {
+			if sets[i] == absorbed {
+				sets[i] = sets[c]
+			}
+		}
+	}
+}
+
+// ellersCarveDown links a random selection of the cells in a row to the cells
+// below them, choosing at least one cell from every set which has a cell below
+// it.  It returns the sets of the cells in the next row, using -1 for cells
+// which weren't reached
+func ellersCarveDown(row []*Cell, sets []int, r *rand.Rand) []int {
+	// Group the columns of the row by set, in the order the sets first appear
+	members := map[int][]int{}
+	order := []int{}
+	for c, set := range sets {
+		if row[c] == nil || row[c].South == nil {
+			continue
+		}
+		if _, ok := members[set]; !ok {
+			order = append(order, set)
+		}
+		members[set] = append(members[set], c)
+	}
+
+	below := make([]int, len(sets))
+	for i := range below {
+		below[i] = -1
+	}
+	for _, set := range order {
+		columns := members[set]
+		r.Shuffle(len(columns), func(i, j int) {
+			columns[i], columns[j] = columns[j], columns[i]
+		})
+		for _, c := range columns[:1+r.Intn(len(columns))] {
+			row[c].Link(row[c].South)
+			below[c] = set
+		}
+	}
+	return below
+}
+
+// AppendRow grows the maze by one row at the bottom and returns the grid.  The
+// new row is carved using the same steps as the rows of Eller's algorithm: each
+// group of connected cells in the current bottom row gets at least one passage
+// down into the new row, and the new row then links all of its sets together.
+// If the maze was perfect before the row was added, it remains perfect.  On a
+// torus, the new row is joined to the top row, and passages which crossed the
+// joined edges pass through the new row instead
+func (g *Grid) AppendRow(r *rand.Rand) *Grid {
+	row := make([]*Cell, g.Columns)
+	for c := range row {
+		cell := NewCell(g.Rows, int64(c))
+		row[c] = &cell
+		if c > 0 {
+			row[c].West = row[c-1]
+			row[c-1].East = row[c]
+		}
+	}
+	if g.cylinder {
+		wrapRow(row)
+	}
+
+	sets := make([]int, g.Columns)
+	for i := range sets {
+		sets[i] = -1
+	}
+	// Columns of a torus with a passage across the joined edges, which will
+	// be routed through the new row
+	wrapped := []int{}
+	if g.Rows > 0 {
+		bottom := g.grid[g.Rows-1]
+
+		// Number the groups of connected cells along the bottom row
+		above := make([]int, g.Columns)
+		label := map[*Cell]int{}
+		for c, cell := range bottom {
+			if cell == nil {
+				continue
+			}
+			if _, ok := label[cell]; !ok {
+				for connected := range Reachable(cell) {
+					label[connected] = c
+				}
+			}
+			above[c] = label[cell]
+		}
+
+		if g.torus {
+			for c, cell := range bottom {
+				if cell != nil && cell.South != nil && cell.South.Row == 0 && cell.Linked(cell.South) {
+					cell.Unlink(cell.South)
+					wrapped = append(wrapped, c)
+				}
+			}
+		}
+		for c := range row {
+			if bottom[c] != nil {
+				row[c].North = bottom[c]
+				bottom[c].South = row[c]
+			}
+		}
+		sets = ellersCarveDown(bottom, above, r)
+		for _, c := range wrapped {
+			bottom[c].Link(row[c])
+			sets[c] = above[c]
+		}
+	}
+
+	// Cells not reached from above begin in sets of their own
+	for i := range sets {
+		if sets[i] < 0 {
+			sets[i] = len(sets) + i
+		}
+	}
+	ellersMergeRow(row, sets, true, r)
+
+	g.grid = append(g.grid, row)
+	g.Rows++
+	if g.mask != nil {
+		g.mask.appendRow()
+	}
+	if g.torus {
+		for c := int64(0); c < g.Columns; c++ {
+			wrapColumn(g.column(c))
+		}
+		for _, c := range wrapped {
+			row[c].Link(g.grid[0][c])
+		}
+	}
+	return g
+}
@@ -0,0 +1,115 @@
+package maze
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestEllers(t *testing.T) {
+	sizes := []struct {
+		rows, columns int64
+	}{
+		{1, 1},
+		{1, 10},
+		{10, 1},
+		{10, 10},
+		{4, 16},
+	}
+	for _, s := range sizes {
+		for seed := int64(0); seed < 5; seed++ {
+			g := NewGrid(s.rows, s.columns)
+			EllersRand(&g, rand.New(rand.NewSource(seed)))
+			if !IsPerfect(&g) {
+				t.Errorf("%dx%d seed %d: maze is not perfect:\n%s", s.rows, s.columns, seed, g.ToString())
+			}
+
+			// The sets after each row are the pieces of the maze carved so far.
+			// Each of them must be carried into the next row
+			for row := int64(0); row+1 < s.rows; row++ {
+				above := func(c *Cell) []*Cell {
+					cells := []*Cell{}
+					for _, l := range c.Links() {
+						if l.Row <= row {
+							cells = append(cells, l)
+						}
+					}
+					return cells
+				}
+				seen := map[*Cell]bool{}
+				for _, cell := range g.grid[row] {
+					if seen[cell] {
+						continue
+					}
+					down := false
+					for c := range reachableVia(cell, above) {
+						seen[c] = true
+						if c.Row == row && c.Linked(c.South) {
+							down = true
+						}
+					}
+					if !down {
+						t.Errorf("%dx%d seed %d: the set containing [%d, %d] has no passage into row %d:\n%s", s.rows, s.columns, seed, cell.Row, cell.Column, row+1, g.ToString())
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestAppendRow(t *testing.T) {
+	grids := []struct {
+		name string
+		grid func(r *rand.Rand) *Grid
+	}{
+		{"Empty", func(r *rand.Rand) *Grid { g := NewGrid(0, 6); return &g }},
+		{"Backtracker", func(r *rand.Rand) *Grid {
+			g := NewGrid(3, 6)
+			RecursiveBacktrackerRand(&g, r)
+			return &g
+		}},
+		{"Ellers", func(r *rand.Rand) *Grid {
+			g := NewGrid(4, 7)
+			EllersRand(&g, r)
+			return &g
+		}},
+		{"Cylinder", func(r *rand.Rand) *Grid {
+			g := NewCylinderGrid(3, 5)
+			RecursiveBacktrackerRand(&g, r)
+			return &g
+		}},
+		{"Torus", func(r *rand.Rand) *Grid {
+			g := NewTorusGrid(3, 5)
+			RecursiveBacktrackerRand(&g, r)
+			return &g
+		}},
+	}
+	for _, tc := range grids {
+		t.Run(tc.name, func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+			g := tc.grid(r)
+			rows := g.Rows
+			for i := 0; i < 6; i++ {
+				// Apart from on a torus, whose wrapping passages are rerouted, the
+				// rows already carved are left alone
+				before := g.ToString()
+				if got := g.AppendRow(r); got != g {
+					t.Fatal("AppendRow() did not return the grid")
+				}
+				rows++
+				if g.Rows != rows || g.Size() != rows*g.Columns {
+					t.Fatalf("grid has %d rows and %d cells after appending, want %d rows", g.Rows, g.Size(), rows)
+				}
+				if !IsPerfect(g) {
+					t.Fatalf("maze is not perfect after appending row %d:\n%s", rows-1, g.ToString())
+				}
+				if !g.torus && rows > 1 {
+					lines := len(strings.Split(before, "\n")) - 3
+					if got := strings.Join(strings.Split(g.ToString(), "\n")[:lines], "\n"); got != strings.Join(strings.Split(before, "\n")[:lines], "\n") {
+						t.Errorf("appending row %d changed the rows above it:\n%s\nwas:\n%s", rows-1, g.ToString(), before)
+					}
+				}
+			}
+		})
+	}
+}
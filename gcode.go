@@ -0,0 +1,80 @@
+package maze
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ToGCode writes G-code which draws the walls of the maze on a pen plotter, with
+// each cell occupying a cellSize x cellSize square measured in millimeters.  The
+// pen is raised with Z1 and lowered with Z0.  Straight walls are drawn as single
+// strokes, and the next wall drawn is always the one nearest the pen, so walls
+// which meet are drawn without lifting the pen
+func (g *Grid) ToGCode(w io.Writer, cellSize float64) error {
+	if !(cellSize > 0) {
+		return fmt.Errorf("invalid G-code cell size: %v", cellSize)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("G21\nG90\nG0 Z1\n")
+	// Plotters place the origin at the bottom-left, so rows are counted upward
+	move := func(command string, x, y int) {
+		fmt.Fprintf(&sb, "%s X%.3f Y%.3f\n", command, float64(x)*cellSize, float64(g.Rows-int64(y))*cellSize)
+	}
+
+	segments := g.WallSegments()
+	used := make([]bool, len(segments))
+	penX, penY, penDown := 0, int(g.Rows), false
+	for range segments {
+		// Find the closest end of any segment not yet drawn
+		best, reverse, bestDist := -1, false, 0
+		for i, s := range segments {
+			if used[i] {
+				continue
+			}
+			for _, end := range []bool{false, true} {
+				x, y := s[0], s[1]
+				if end {
+					x, y = s[2], s[3]
+				}
+				dist := abs(x-penX) + abs(y-penY)
+				if best < 0 || dist < bestDist {
+					best, reverse, bestDist = i, end, dist
+				}
+			}
+		}
+
+		s := segments[best]
+		used[best] = true
+		if reverse {
+			s = [4]int{s[2], s[3], s[0], s[1]}
+		}
+		if bestDist > 0 {
+			if penDown {
+				sb.WriteString("G0 Z1\n")
+			}
+			move("G0", s[0], s[1])
+			penDown = false
+		}
+		if !penDown {
+			sb.WriteString("G1 Z0\n")
+			penDown = true
+		}
+		move("G1", s[2], s[3])
+		penX, penY = s[2], s[3]
+	}
+
+	sb.WriteString("G0 Z1\n")
+	move("G0", 0, int(g.Rows))
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// abs returns the absolute value of an integer
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
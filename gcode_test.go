@@ -0,0 +1,123 @@
+package maze
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestToGCode(t *testing.T) {
+	g := linkedGrid(1, 2, [][4]int64{{0, 0, 0, 1}})
+	var out bytes.Buffer
+	if err := g.ToGCode(&out, 10); err != nil {
+		t.Fatal(err)
+	}
+	// The border is drawn in a single stroke starting from the origin
+	want := "G21\nG90\nG0 Z1\n" +
+		"G1 Z0\n" +
+		"G1 X20.000 Y0.000\n" +
+		"G1 X20.000 Y10.000\n" +
+		"G1 X0.000 Y10.000\n" +
+		"G1 X0.000 Y0.000\n" +
+		"G0 Z1\n" +
+		"G0 X0.000 Y0.000\n"
+	if out.String() != want {
+		t.Errorf("ToGCode() =\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestToGCodeDrawsEveryWall(t *testing.T) {
+	g := NewGrid(7, 9)
+	RecursiveBacktrackerRand(&g, rand.New(rand.NewSource(1)))
+	var out bytes.Buffer
+	if err := g.ToGCode(&out, 2.5); err != nil {
+		t.Fatal(err)
+	}
+
+	// Split every stroke drawn with the pen down into unit lengths of wall,
+	// using the plotter's coordinates with the origin at the bottom-left
+	drawn := map[[4]int]int{}
+	x, y, down := 0, 0, false
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "G1 Z0":
+			down = true
+		case line == "G0 Z1":
+			down = false
+		case strings.HasPrefix(line, "G0 X") || strings.HasPrefix(line, "G1 X"):
+			var cmd string
+			var fx, fy float64
+			if _, err := fmt.Sscanf(line, "%s X%f Y%f", &cmd, &fx, &fy); err != nil {
+				t.Fatalf("unparseable move %q: %v", line, err)
+			}
+			nx, ny := int(math.Round(fx/2.5)), int(math.Round(fy/2.5))
+			if cmd == "G1" {
+				if !down {
+					t.Fatalf("%q draws with the pen raised", line)
+				}
+				if nx != x && ny != y {
+					t.Fatalf("%q draws a diagonal line", line)
+				}
+				for x != nx || y != ny {
+					sx, sy := x+sign(nx-x), y+sign(ny-y)
+					drawn[unitWall(x, y, sx, sy)]++
+					x, y = sx, sy
+				}
+			}
+			x, y = nx, ny
+		}
+	}
+
+	want := map[[4]int]int{}
+	for _, s := range g.WallSegments() {
+		x, y := s[0], int(g.Rows)-s[1]
+		ex, ey := s[2], int(g.Rows)-s[3]
+		for x != ex || y != ey {
+			sx, sy := x+sign(ex-x), y+sign(ey-y)
+			want[unitWall(x, y, sx, sy)]++
+			x, y = sx, sy
+		}
+	}
+	if len(drawn) != len(want) {
+		t.Errorf("drew %d unit walls, want %d", len(drawn), len(want))
+	}
+	for w, n := range drawn {
+		if n != 1 || want[w] != 1 {
+			t.Errorf("wall %v was drawn %d times, and is standing %d times", w, n, want[w])
+		}
+	}
+}
+
+func TestToGCodeInvalidSize(t *testing.T) {
+	g := NewGrid(2, 2)
+	for _, size := range []float64{0, -1, math.NaN()} {
+		if err := g.ToGCode(&bytes.Buffer{}, size); err == nil {
+			t.Errorf("ToGCode(%v) succeeded, want an error", size)
+		}
+	}
+}
+
+// sign returns -1, 0, or 1 matching the sign of x
+func sign(x int) int {
+	switch {
+	case x < 0:
+		return -1
+	case x > 0:
+		return 1
+	}
+	return 0
+}
+
+// unitWall returns a unit length of wall with its ends in a canonical order
+func unitWall(x1, y1, x2, y2 int) [4]int {
+	if x2 < x1 || y2 < y1 {
+		return [4]int{x2, y2, x1, y1}
+	}
+	return [4]int{x1, y1, x2, y2}
+}
@@ -0,0 +1,114 @@
+package maze
+
+import (
+	"math/rand"
+)
+
+// GenerateGoalCentered carves a perfect maze for a "journey to the center"
+// puzzle.  The goal is the cell at the geometric center of the grid, and the
+// maze is grown outward from it so that the longest corridors lead inward.  The
+// start is the border cell farthest from the goal.  Any existing links in the
+// grid are removed first
+func GenerateGoalCentered(g *Grid, r *rand.Rand) (start, goal *Cell) {
+	if g.Size() == 0 {
+		return nil, nil
+	}
+
+	g.Reset()
+	goal = g.At(g.Rows/2, g.Columns/2)
+	if goal == nil {
+		// The center is masked out, so grow the maze from the nearest enabled cell
+		nearest := -1
+		for _, cell := range g.Cells() {
+			d := abs(int(cell.Row-g.Rows/2)) + abs(int(cell.Column-g.Columns/2))
+			if nearest < 0 || d < nearest {
+				goal, nearest = cell, d
+			}
+		}
+	}
+	recursiveBacktracker(g, goal, r, nil)
+
+	distances := ComputeDistances(goal)
+	farthest := int64(-1)
+	for _, cell := range g.Cells() {
+		if d, _ := distances.Get(cell); g.onBorder(cell) && d > farthest {
+			start, farthest = cell, d
+		}
+	}
+	return start, goal
+}
+
+// GenerateSolved creates a rows x cols maze using the provided generator and
+// returns it along with a start and goal and the solution between them.  The
+// start and goal are the ends of the maze's longest path
+func GenerateSolved(rows, cols int64, gen func(*Grid, *rand.Rand), r *rand.Rand) (*Grid, *Cell, *Cell, []*Cell) {
+	g := NewGrid(rows, cols)
+	gen(&g, r)
+	path, _ := LongestPath(&g)
+	if len(path) == 0 {
+		return &g, nil, nil, nil
+	}
+	return &g, path[0], path[len(path)-1], path
+}
+
+// HybridMaze creates a maze by running one generator on the rows above splitRow
+// and another on the rows from splitRow down, then joining the two halves with
+// a single passage across the seam.  If both generators produce perfect mazes,
+// so does HybridMaze, unless a mask leaves either half disconnected or leaves no
+// place for the seam.  If splitRow leaves either half empty, the other generator
+// is run on the whole grid
+func HybridMaze(g *Grid, top, bottom func(*Grid, *rand.Rand), splitRow int64, r *rand.Rand) {
+	if splitRow <= 0 {
+		bottom(g, r)
+		return
+	}
+	if splitRow >= g.Rows {
+		top(g, r)
+		return
+	}
+
+	upper, lower := g.rowRange(0, splitRow), g.rowRange(splitRow, g.Rows)
+	top(upper, r)
+	bottom(lower, r)
+	for _, half := range []struct {
+		grid   *Grid
+		offset int64
+	}{{upper, 0}, {lower, splitRow}} {
+		offset := half.offset
+		ForEachAdjacentPair(half.grid, func(a, b *Cell) {
+			if a.Linked(b) {
+				g.At(a.Row+offset, a.Column).Link(g.At(b.Row+offset, b.Column))
+			}
+		})
+	}
+
+	seams := []*Cell{}
+	for _, cell := range g.grid[splitRow-1] {
+		if cell != nil && cell.South != nil {
+			seams = append(seams, cell)
+		}
+	}
+	if len(seams) > 0 {
+		seam := seams[r.Intn(len(seams))]
+		seam.Link(seam.South)
+	}
+}
+
+// rowRange returns a new unlinked grid with the same columns, mask, and shape as
+// this grid, containing the rows from the first row given up to but not
+// including the second.  The north and south edges of a torus only remain
+// joined if every row is included
+func (g *Grid) rowRange(from, to int64) *Grid {
+	sub := &Grid{
+		Rows:     to - from,
+		Columns:  g.Columns,
+		grid:     make([][]*Cell, to-from),
+		cylinder: g.cylinder,
+		torus:    g.torus && from == 0 && to == g.Rows}
+	if g.mask != nil {
+		sub.mask = g.mask.rowRange(from, to)
+	}
+	sub.prepareGrid()
+	sub.configureCells()
+	return sub
+}
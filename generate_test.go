@@ -0,0 +1,132 @@
+package maze
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestGenerateGoalCentered(t *testing.T) {
+	sizes := []struct {
+		rows, columns int64
+	}{
+		{9, 9},
+		{8, 10},
+		{6, 6},
+	}
+	for _, s := range sizes {
+		for seed := int64(0); seed < 10; seed++ {
+			g := NewGrid(s.rows, s.columns)
+			start, goal := GenerateGoalCentered(&g, rand.New(rand.NewSource(seed)))
+			if !IsPerfect(&g) {
+				t.Fatalf("%dx%d seed %d: maze is not perfect:\n%s", s.rows, s.columns, seed, g.ToString())
+			}
+			if goal != g.At(s.rows/2, s.columns/2) {
+				t.Errorf("%dx%d seed %d: goal is [%d, %d], want the center", s.rows, s.columns, seed, goal.Row, goal.Column)
+			}
+			if !g.onBorder(start) {
+				t.Errorf("%dx%d seed %d: start [%d, %d] is not on the border", s.rows, s.columns, seed, start.Row, start.Column)
+			}
+			// The goal is farther from the start than at least three quarters of
+			// the maze
+			distances := ComputeDistances(start)
+			toGoal, _ := distances.Get(goal)
+			closer := 0
+			for _, cell := range g.Cells() {
+				if d, _ := distances.Get(cell); d < toGoal {
+					closer++
+				}
+			}
+			if 4*closer < 3*int(g.Size()) {
+				t.Errorf("%dx%d seed %d: only %d of %d cells are closer to the start than the goal", s.rows, s.columns, seed, closer, g.Size())
+			}
+		}
+	}
+}
+
+func TestGenerateGoalCenteredMasked(t *testing.T) {
+	g := NewMaskedGrid(maskFromString(t, "...\n.X.\n..."))
+	start, goal := GenerateGoalCentered(g, rand.New(rand.NewSource(1)))
+	if !IsPerfect(g) {
+		t.Fatalf("maze is not perfect:\n%s", g.ToString())
+	}
+	// The nearest enabled cell to the masked center, in row-major order
+	if goal != g.At(0, 1) || start == nil || start == goal {
+		t.Errorf("GenerateGoalCentered() = %v, %v, want a goal at [0, 1]", start, goal)
+	}
+
+	empty := NewGrid(0, 0)
+	if start, goal := GenerateGoalCentered(&empty, rand.New(rand.NewSource(1))); start != nil || goal != nil {
+		t.Errorf("GenerateGoalCentered() of an empty grid = %v, %v, want nil", start, goal)
+	}
+}
+
+func TestGenerateSolved(t *testing.T) {
+	for _, gen := range generators {
+		t.Run(gen.name, func(t *testing.T) {
+			g, start, goal, path := GenerateSolved(6, 9, gen.generate, rand.New(rand.NewSource(1)))
+			if g.Rows != 6 || g.Columns != 9 || !IsPerfect(g) {
+				t.Fatalf("GenerateSolved() returned a %dx%d grid, want a perfect 6x9 maze:\n%s", g.Rows, g.Columns, g.ToString())
+			}
+			if len(path) == 0 || path[0] != start || path[len(path)-1] != goal {
+				t.Fatalf("solution does not run from the start to the goal")
+			}
+			for i := 1; i < len(path); i++ {
+				if !path[i-1].Linked(path[i]) {
+					t.Fatalf("solution steps from [%d, %d] to [%d, %d] through a wall", path[i-1].Row, path[i-1].Column, path[i].Row, path[i].Column)
+				}
+			}
+			// The start and goal are the ends of the longest path, so nothing is
+			// farther from the start than the goal
+			if _, max := ComputeDistances(start).Max(); int64(len(path)-1) != max {
+				t.Errorf("solution has %d steps, but a cell is %d steps from the start", len(path)-1, max)
+			}
+			if shortest, _ := ShortestPath(start, goal); len(shortest) != len(path) {
+				t.Errorf("solution has %d cells, but the shortest path has %d", len(path), len(shortest))
+			}
+		})
+	}
+}
+
+func TestGenerateSolvedEmpty(t *testing.T) {
+	g, start, goal, path := GenerateSolved(0, 0, RecursiveBacktrackerRand, rand.New(rand.NewSource(1)))
+	if g == nil || start != nil || goal != nil || path != nil {
+		t.Errorf("GenerateSolved(0, 0) = %v, %v, %v, %v, want an empty grid and no solution", g, start, goal, path)
+	}
+}
+
+func TestHybridMaze(t *testing.T) {
+	for seed := int64(0); seed < 5; seed++ {
+		g := NewGrid(20, 20)
+		HybridMaze(&g, BinaryTreeRand, RecursiveBacktrackerRand, 10, rand.New(rand.NewSource(seed)))
+		if !IsPerfect(&g) {
+			t.Fatalf("seed %d: maze is not perfect:\n%s", seed, g.ToString())
+		}
+
+		// Every cell of the binary tree half but its corner is linked north or
+		// east, but not both, which the backtracker half doesn't preserve.  The
+		// backtracker also leaves far fewer dead ends
+		biased := [2]int{}
+		ends := [2]int{}
+		for _, cell := range g.Cells() {
+			half := 0
+			if cell.Row >= 10 {
+				half = 1
+			}
+			if cell.Linked(cell.North) != cell.Linked(cell.East) {
+				biased[half]++
+			}
+			if cell.linkCount() == 1 {
+				ends[half]++
+			}
+		}
+		if biased[0] != 199 {
+			t.Errorf("seed %d: %d cells of the binary tree half are linked either north or east, want 199", seed, biased[0])
+		}
+		if biased[1] >= 150 {
+			t.Errorf("seed %d: %d cells of the backtracker half are linked either north or east, want fewer", seed, biased[1])
+		}
+		if ends[0] <= ends[1] {
+			t.Errorf("seed %d: binary tree half has %d dead ends and backtracker half has %d, want more in the binary tree half", seed, ends[0], ends[1])
+		}
+	}
+}
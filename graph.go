@@ -0,0 +1,159 @@
+package maze
+
+import (
+	"math/rand"
+)
+
+// Node is a cell of a grid of any shape.  Algorithms written against Node and
+// Graph run on any grid which implements them
+type Node interface {
+	// NeighborNodes returns the cells adjacent to this cell
+	NeighborNodes() []Node
+	// LinkedNodes returns the cells this cell is linked to
+	LinkedNodes() []Node
+	// LinkNode links this cell to another cell of the same grid bidirectionally
+	LinkNode(neighbor Node)
+}
+
+// Graph is a grid of any shape, made up of cells which can be linked to their
+// neighbors
+type Graph interface {
+	// Nodes returns all of the cells in the grid
+	Nodes() []Node
+	// RandomNode returns a random cell from the grid chosen using the provided
+	// random source, or nil if the grid has no cells
+	RandomNode(r *rand.Rand) Node
+	// Size returns the number of cells in the grid
+	Size() int64
+}
+
+var _ Graph = (*Grid)(nil)
+var _ Node = (*Cell)(nil)
+
+// NeighborNodes returns the direct neighbors of this cell, in the same order as
+// Neighbors
+func (c *Cell) NeighborNodes() []Node {
+	return cellNodes(c.Neighbors())
+}
+
+// LinkedNodes returns the cells this cell is linked to, in the same order as
+// Links
+func (c *Cell) LinkedNodes() []Node {
+	return cellNodes(c.Links())
+}
+
+// LinkNode links this cell to another bidirectionally.  The other cell must be a
+// *Cell
+func (c *Cell) LinkNode(neighbor Node) {
+	c.Link(neighbor.(*Cell))
+}
+
+// Nodes returns all of the enabled cells in the grid in row-major order
+func (g *Grid) Nodes() []Node {
+	return cellNodes(g.Cells())
+}
+
+// RandomNode returns a random enabled cell from the grid chosen using the
+// provided random source, or nil if the grid has no enabled cells
+func (g *Grid) RandomNode(r *rand.Rand) Node {
+	if g.Size() == 0 {
+		return nil
+	}
+	if c := g.RandomCellRand(r); c != nil {
+		return c
+	}
+	return nil
+}
+
+// cellNodes converts a list of cells to a list of nodes
+func cellNodes(cells []*Cell) []Node {
+	nodes := make([]Node, len(cells))
+	for i, c := range cells {
+		nodes[i] = c
+	}
+	return nodes
+}
+
+// GraphDistances returns the number of steps along linked passages from root to
+// every cell of a grid of any shape which can be reached from it
+func GraphDistances(root Node) map[Node]int64 {
+	dist := map[Node]int64{root: 0}
+	frontier := []Node{root}
+	for len(frontier) > 0 {
+		cell := frontier[0]
+		frontier = frontier[1:]
+		for _, n := range cell.LinkedNodes() {
+			if _, ok := dist[n]; !ok {
+				dist[n] = dist[cell] + 1
+				frontier = append(frontier, n)
+			}
+		}
+	}
+	return dist
+}
+
+// RecursiveBacktrackerGraph carves a perfect maze in a grid of any shape with a
+// depth-first random walk which backs up whenever it reaches a dead end
+func RecursiveBacktrackerGraph(g Graph, r *rand.Rand) {
+	start := g.RandomNode(r)
+	if start == nil {
+		return
+	}
+	visited := map[Node]bool{start: true}
+	stack := []Node{start}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		candidates := []Node{}
+		for _, n := range current.NeighborNodes() {
+			if !visited[n] {
+				candidates = append(candidates, n)
+			}
+		}
+
+		if len(candidates) == 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		next := candidates[r.Intn(len(candidates))]
+		current.LinkNode(next)
+		visited[next] = true
+		stack = append(stack, next)
+	}
+}
+
+// nodeLinks records the cells a cell of a polar, hexagonal, or triangular grid
+// is linked to, in the order the links were made.  Cells have only a handful of
+// links, so a list is both smaller and faster than a map
+type nodeLinks []Node
+
+// add records a link to a cell
+func (l *nodeLinks) add(n Node) {
+	if !l.has(n) {
+		*l = append(*l, n)
+	}
+}
+
+// remove forgets the link to a cell
+func (l *nodeLinks) remove(n Node) {
+	for i, x := range *l {
+		if x == n {
+			*l = append((*l)[:i], (*l)[i+1:]...)
+			return
+		}
+	}
+}
+
+// has returns true if there is a link to a cell
+func (l nodeLinks) has(n Node) bool {
+	for _, x := range l {
+		if x == n {
+			return true
+		}
+	}
+	return false
+}
+
+// nodes returns a copy of the linked cells
+func (l nodeLinks) nodes() []Node {
+	return append([]Node{}, l...)
+}
@@ -0,0 +1,67 @@
+package maze
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// graphIsPerfect returns true if every cell of a grid of any shape can be
+// reached from every other by exactly one path
+func graphIsPerfect(g Graph) bool {
+	nodes := g.Nodes()
+	if len(nodes) == 0 {
+		return true
+	}
+	ends := int64(0)
+	for _, n := range nodes {
+		ends += int64(len(n.LinkedNodes()))
+	}
+	return int64(len(GraphDistances(nodes[0]))) == g.Size() && ends/2 == g.Size()-1
+}
+
+func TestGridAsGraph(t *testing.T) {
+	sizes := []struct {
+		name          string
+		rows, columns int64
+	}{
+		{"Empty", 0, 0},
+		{"SingleCell", 1, 1},
+		{"Rectangle", 6, 9},
+	}
+	for _, tc := range sizes {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGrid(tc.rows, tc.columns)
+			if int64(len(g.Nodes())) != g.Size() {
+				t.Fatalf("Nodes() has %d cells, want %d", len(g.Nodes()), g.Size())
+			}
+			RecursiveBacktrackerGraph(&g, rand.New(rand.NewSource(1)))
+			if !graphIsPerfect(&g) {
+				t.Fatalf("maze is not perfect:\n%s", g.ToString())
+			}
+			for _, cell := range g.Cells() {
+				want := ComputeDistances(cell)
+				got := GraphDistances(cell)
+				if len(got) != len(want.cells) {
+					t.Fatalf("GraphDistances() reached %d cells, want %d", len(got), len(want.cells))
+				}
+				for n, d := range got {
+					if w, _ := want.Get(n.(*Cell)); w != d {
+						t.Fatalf("GraphDistances() = %d, want %d", d, w)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGridRandomNode(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	empty := NewGrid(0, 3)
+	if n := empty.RandomNode(r); n != nil {
+		t.Errorf("RandomNode() of an empty grid = %v, want nil", n)
+	}
+	g := NewGrid(1, 1)
+	if n := g.RandomNode(r); n != g.At(0, 0) {
+		t.Errorf("RandomNode() = %v, want the only cell", n)
+	}
+}
@@ -14,14 +14,35 @@ const (
 type Grid struct {
 	// Rows and Columns indicate the size of the grid
 	Rows, Columns int64
-	// The cells in the grid
+	// The cells in the grid.  Cells disabled by the mask are nil
 	grid [][]*Cell
+	// The cells of the grid which are enabled, or nil if every cell is enabled
+	mask *Mask
+	// Whether the east edge of the grid wraps around to the west edge
+	cylinder bool
+	// Whether the south edge of the grid also wraps around to the north edge
+	torus bool
+	// The sides of cells along the border which have been opened
+	openings map[opening]bool
 }
 
-// NewGrid creates a new rectangular grid with all cells connected to their neighbors
+// NewGrid creates a new rectangular grid.  Every cell knows its neighbors, but
+// no cells are linked, so the grid begins with every wall standing.  If the
+// dimensions are negative the program exits; use NewGridChecked to handle the
+// error instead
 func NewGrid(rows, columns int64) Grid {
+	g, err := NewGridChecked(rows, columns)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return *g
+}
+
+// NewGridChecked creates a new rectangular grid like NewGrid, but returns an
+// error if the dimensions are negative
+func NewGridChecked(rows, columns int64) (*Grid, error) {
 	if rows < 0 || columns < 0 {
-		log.Fatalf("Grid dimensions invalid: [%d, %d]", rows, columns)
+		return nil, fmt.Errorf("grid dimensions invalid: [%d, %d]", rows, columns)
 	}
 	g := Grid{
 		Rows:    rows,
@@ -29,10 +50,141 @@ func NewGrid(rows, columns int64) Grid {
 		grid:    make([][]*Cell, rows)}
 	g.prepareGrid()
 	g.configureCells()
+	return &g, nil
+}
+
+// NewFullyLinkedGrid creates a new rectangular grid in which every cell is
+// linked to all of its neighbors, leaving only the outer border standing
+func NewFullyLinkedGrid(rows, columns int64) Grid {
+	g := NewGrid(rows, columns)
+	g.linkAll()
 	return g
 }
 
-// At accesses a cell from the grid
+// NewCylinderGrid creates a new rectangular grid whose east and west edges are
+// joined, so that the last cell of each row is the West neighbor of the first
+// and the first is the East neighbor of the last.  If the dimensions are
+// negative or there are fewer than three columns the program exits; use
+// NewCylinderGridChecked to handle the error instead
+func NewCylinderGrid(rows, columns int64) Grid {
+	g, err := NewCylinderGridChecked(rows, columns)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return *g
+}
+
+// NewCylinderGridChecked creates a new cylinder grid like NewCylinderGrid, but
+// returns an error if the dimensions are negative or there are fewer than three
+// columns.  With only two columns the first and last cells of each row would be
+// neighbors on both sides
+func NewCylinderGridChecked(rows, columns int64) (*Grid, error) {
+	if err := checkWrap(rows, columns, false); err != nil {
+		return nil, err
+	}
+	g, err := NewGridChecked(rows, columns)
+	if err != nil {
+		return nil, err
+	}
+	g.cylinder = true
+	g.configureCells()
+	return g, nil
+}
+
+// NewTorusGrid creates a new rectangular grid whose east and west edges are
+// joined as in NewCylinderGrid, and whose north and south edges are joined as
+// well, so that the grid has no outer border.  When rendered, passages across
+// the joined edges appear as gaps in the border.  If the dimensions are negative
+// or there are fewer than three rows or columns the program exits; use
+// NewTorusGridChecked to handle the error instead
+func NewTorusGrid(rows, columns int64) Grid {
+	g, err := NewTorusGridChecked(rows, columns)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return *g
+}
+
+// NewTorusGridChecked creates a new torus grid like NewTorusGrid, but returns an
+// error if the dimensions are negative or there are fewer than three rows or
+// columns
+func NewTorusGridChecked(rows, columns int64) (*Grid, error) {
+	if err := checkWrap(rows, columns, true); err != nil {
+		return nil, err
+	}
+	g, err := NewGridChecked(rows, columns)
+	if err != nil {
+		return nil, err
+	}
+	g.cylinder = true
+	g.torus = true
+	g.configureCells()
+	return g, nil
+}
+
+// checkWrap returns an error if a grid is too small for its east and west edges
+// to be joined, or its north and south edges as well if torus is true
+func checkWrap(rows, columns int64, torus bool) error {
+	if columns < 3 {
+		return fmt.Errorf("joining the east and west edges requires at least 3 columns, not %d", columns)
+	}
+	if torus && rows < 3 {
+		return fmt.Errorf("joining the north and south edges requires at least 3 rows, not %d", rows)
+	}
+	return nil
+}
+
+// wrapRow makes the first and last cells of a row neighbors of each other
+func wrapRow(row []*Cell) {
+	if len(row) < 3 || row[0] == nil || row[len(row)-1] == nil {
+		return
+	}
+	first, last := row[0], row[len(row)-1]
+	first.West, last.East = last, first
+}
+
+// wrapColumn makes the first and last cells of a column neighbors of each other
+func wrapColumn(column []*Cell) {
+	if len(column) < 3 || column[0] == nil || column[len(column)-1] == nil {
+		return
+	}
+	first, last := column[0], column[len(column)-1]
+	first.North, last.South = last, first
+}
+
+// wrapsAround returns true if two neighboring cells are only neighbors because
+// the edges of the grid are joined
+func wrapsAround(a, b *Cell) bool {
+	return manhattan(a, b) > 1
+}
+
+// column returns the cells in a column of the grid from top to bottom.  Cells
+// disabled by the mask are nil
+func (g *Grid) column(c int64) []*Cell {
+	ret := make([]*Cell, len(g.grid))
+	for r, row := range g.grid {
+		ret[r] = row[c]
+	}
+	return ret
+}
+
+// NewMaskedGrid creates a new rectangular grid the size of the mask containing
+// only the cells the mask enables.  Disabled cells are not neighbors of any
+// other cell and are never visited, so mazes are carved only within the enabled
+// region.  Later changes to the mask do not affect the grid
+func NewMaskedGrid(mask *Mask) *Grid {
+	g := Grid{
+		Rows:    mask.Rows,
+		Columns: mask.Columns,
+		grid:    make([][]*Cell, mask.Rows),
+		mask:    mask.clone()}
+	g.prepareGrid()
+	g.configureCells()
+	return &g
+}
+
+// At accesses a cell from the grid.  It returns nil for cells outside the grid
+// and cells disabled by the mask
 func (g *Grid) At(row, column int64) *Cell {
 	if row < 0 || column < 0 || row >= g.Rows || column >= g.Columns {
 		return nil
@@ -45,6 +197,9 @@ func (g *Grid) prepareGrid() {
 	for r := int64(0); r < g.Rows; r++ {
 		g.grid[r] = make([]*Cell, g.Columns)
 		for c := int64(0); c < g.Columns; c++ {
+			if g.mask != nil && !g.mask.Enabled(r, c) {
+				continue
+			}
 			cell := NewCell(r, c)
 			g.grid[r][c] = &cell
 		}
@@ -53,15 +208,46 @@ func (g *Grid) prepareGrid() {
 
 // configureCells establishes links between cells and their neighbors
 func (g *Grid) configureCells() {
-	for cell := range g.AllCells() {
+	for _, cell := range g.Cells() {
 		cell.North = g.At(cell.Row-1, cell.Column)
 		cell.South = g.At(cell.Row+1, cell.Column)
 		cell.West = g.At(cell.Row, cell.Column-1)
 		cell.East = g.At(cell.Row, cell.Column+1)
 	}
+	if g.cylinder {
+		for _, row := range g.grid {
+			wrapRow(row)
+		}
+	}
+	if g.torus {
+		for c := int64(0); c < g.Columns; c++ {
+			wrapColumn(g.column(c))
+		}
+	}
+	for _, cell := range g.Cells() {
+		cell.cacheNeighbors()
+	}
+}
+
+// Reset removes every link between cells in the grid, leaving every wall
+// standing so that a new maze can be generated in it.  The cells and their
+// neighbors are kept
+func (g *Grid) Reset() {
+	for _, cell := range g.Cells() {
+		cell.clearLinks()
+	}
 }
 
-// AllRows returns a row of cells in the grid at a time
+// linkAll links every cell in the grid to all of its neighbors
+func (g *Grid) linkAll() {
+	ForEachAdjacentPair(g, func(a, b *Cell) {
+		a.Link(b)
+	})
+}
+
+// AllRows returns a row of cells in the grid at a time.  Cells disabled by the
+// mask are nil.  The channel must be drained or its goroutine will leak; prefer
+// CellRows
 func (g *Grid) AllRows() <-chan []*Cell {
 	c := make(chan []*Cell)
 	go func() {
@@ -73,63 +259,233 @@ func (g *Grid) AllRows() <-chan []*Cell {
 	return c
 }
 
-// AllCells iterates over all of the cells in the grid
+// AllCells iterates over all of the enabled cells in the grid.  The channel
+// must be drained or its goroutine will leak; prefer Cells
 func (g *Grid) AllCells() <-chan *Cell {
 	c := make(chan *Cell)
 	go func() {
-		for _, row := range g.grid {
-			for _, cell := range row {
-				c <- cell
-			}
+		for _, cell := range g.Cells() {
+			c <- cell
 		}
 		close(c)
 	}()
 	return c
 }
 
-// RandomCell returns a random cell from the grid
+// CellRows returns the rows of cells in the grid.  Cells disabled by the mask
+// are nil.  The returned slices may be modified without affecting the grid
+func (g *Grid) CellRows() [][]*Cell {
+	rows := make([][]*Cell, len(g.grid))
+	for r, row := range g.grid {
+		rows[r] = append([]*Cell(nil), row...)
+	}
+	return rows
+}
+
+// Cells returns all of the enabled cells in the grid in row-major order
+func (g *Grid) Cells() []*Cell {
+	cells := make([]*Cell, 0, g.Size())
+	for _, row := range g.grid {
+		for _, cell := range row {
+			if cell != nil {
+				cells = append(cells, cell)
+			}
+		}
+	}
+	return cells
+}
+
+// ForEachAdjacentPair calls fn exactly once for every pair of orthogonally
+// adjacent cells in the grid, regardless of whether they are linked
+func ForEachAdjacentPair(g *Grid, fn func(a, b *Cell)) {
+	for _, row := range g.grid {
+		for _, cell := range row {
+			if cell == nil {
+				continue
+			}
+			if cell.East != nil {
+				fn(cell, cell.East)
+			}
+			if cell.South != nil {
+				fn(cell, cell.South)
+			}
+		}
+	}
+}
+
+// RandomCell returns a random enabled cell from the grid
 func (g *Grid) RandomCell() *Cell {
-	return g.At(rand.Int63n(g.Rows), rand.Int63n(g.Columns))
+	return g.RandomCellRand(defaultRand)
+}
+
+// firstCell returns the first cell of the grid in row-major order, or nil if
+// the grid has no cells
+func (g *Grid) firstCell() *Cell {
+	for _, row := range g.grid {
+		for _, cell := range row {
+			if cell != nil {
+				return cell
+			}
+		}
+	}
+	return nil
+}
+
+// onBorder returns true if a cell lies along the outer edge of the grid
+func (g *Grid) onBorder(c *Cell) bool {
+	return c.Row == 0 || c.Column == 0 || c.Row == g.Rows-1 || c.Column == g.Columns-1
+}
+
+// RandomCellRand returns a random enabled cell from the grid chosen using the
+// provided random source.  If a mask disables every cell, it returns nil
+func (g *Grid) RandomCellRand(r *rand.Rand) *Cell {
+	if g.mask != nil {
+		return g.At(g.mask.randomLocation(r))
+	}
+	return g.At(r.Int63n(g.Rows), r.Int63n(g.Columns))
 }
 
-// Size returns the number of cells in the grid
+// Size returns the number of enabled cells in the grid
 func (g *Grid) Size() int64 {
+	if g.mask != nil {
+		return g.mask.Count()
+	}
 	return g.Rows * g.Columns
 }
 
-// Unicode light box drawing characters
+// Clone returns an independent copy of the grid with the same dimensions, mask,
+// shape, border openings, and links.  Changing the links of either grid does
+// not affect the other.  Links to cells outside the grid are not copied
+func (g *Grid) Clone() *Grid {
+	clone := g.rowRange(0, g.Rows)
+	for o := range g.openings {
+		_ = clone.OpenBorder(clone.At(o.cell.Row, o.cell.Column), o.dir)
+	}
+	for _, cell := range g.Cells() {
+		copied := clone.At(cell.Row, cell.Column)
+		for _, linked := range cell.Links() {
+			if g.At(linked.Row, linked.Column) == linked {
+				copied.LinkOneWay(clone.At(linked.Row, linked.Column))
+			}
+		}
+	}
+	return clone
+}
+
+// Equal returns true if two grids have the same dimensions, the same cells, and
+// the same links.  Cells are compared by their positions, so independently
+// constructed grids can be equal
+func Equal(a, b *Grid) bool {
+	if a.Rows != b.Rows || a.Columns != b.Columns {
+		return false
+	}
+	for row := int64(0); row < a.Rows; row++ {
+		for col := int64(0); col < a.Columns; col++ {
+			ca, cb := a.At(row, col), b.At(row, col)
+			if (ca == nil) != (cb == nil) {
+				return false
+			}
+			if ca != nil && !sameLinks(ca, cb) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// sameLinks returns true if two cells are linked to cells in the same positions
+func sameLinks(a, b *Cell) bool {
+	positions := map[[2]int64]bool{}
+	for _, l := range a.Links() {
+		positions[[2]int64{l.Row, l.Column}] = true
+	}
+	links := b.Links()
+	if len(links) != len(positions) {
+		return false
+	}
+	for _, l := range links {
+		if !positions[[2]int64{l.Row, l.Column}] {
+			return false
+		}
+	}
+	return true
+}
+
+// WallStyle selects the characters used to draw walls in a textual
+// representation of a maze
+type WallStyle int
+
+// The available wall styles
 const (
-	horizontal      = '\u2500' // ─
-	vertical        = '\u2502' // │
-	cornerDownRight = '\u250c' // ┌
-	cornerDownLeft  = '\u2510' // ┐
-	cornerUpRight   = '\u2514' // └
-	cornerUpLeft    = '\u2518' // ┘
-	verticalRight   = '\u251c' // ├
-	verticalLeft    = '\u2524' // ┤
-	horizontalDown  = '\u252c' // ┬
-	horizontalUp    = '\u2534' // ┴
-	intersection    = '\u253c' // ┼
+	// LightWalls draws walls with light box drawing characters, like ┼
+	LightWalls WallStyle = iota
+	// HeavyWalls draws walls with heavy box drawing characters, like ╋
+	HeavyWalls
+	// DoubleWalls draws walls with double-line box drawing characters, like ╬
+	DoubleWalls
+	// ASCIIWalls draws walls with the ASCII characters +, -, and |
+	ASCIIWalls
 )
 
+// boxGlyphs is a set of characters for drawing walls and the corners where
+// they meet
+type boxGlyphs struct {
+	horizontal, vertical                                         rune
+	cornerDownRight, cornerDownLeft, cornerUpRight, cornerUpLeft rune
+	verticalRight, verticalLeft, horizontalDown, horizontalUp    rune
+	intersection                                                 rune
+}
+
+// wallGlyphs holds the characters used by each wall style
+var wallGlyphs = map[WallStyle]boxGlyphs{
+	LightWalls:  {'─', '│', '┌', '┐', '└', '┘', '├', '┤', '┬', '┴', '┼'},
+	HeavyWalls:  {'━', '┃', '┏', '┓', '┗', '┛', '┣', '┫', '┳', '┻', '╋'},
+	DoubleWalls: {'═', '║', '╔', '╗', '╚', '╝', '╠', '╣', '╦', '╩', '╬'},
+	ASCIIWalls:  {'-', '|', '+', '+', '+', '+', '+', '+', '+', '+', '+'},
+}
+
+// glyphs returns the characters used by a wall style.  Unknown styles use light
+// box drawing characters
+func (s WallStyle) glyphs() boxGlyphs {
+	if b, ok := wallGlyphs[s]; ok {
+		return b
+	}
+	return wallGlyphs[LightWalls]
+}
+
 // ToString creates a textual representation of the maze grid
 func (g *Grid) ToString() string {
-	return g.toString(3, 1)
+	return g.toString(LightWalls, 3, 1, nil)
+}
+
+// ToStringStyle creates a textual representation of the maze grid using the
+// given style of wall
+func (g *Grid) ToStringStyle(style WallStyle) string {
+	return g.toString(style, 3, 1, nil)
 }
 
-// toString creates a textual representation of the maze grid
-func (g *Grid) toString(horizontalSize, verticalSize int) string {
+// ToStringSized creates a textual representation of the maze grid in which each
+// cell is horizontalSize characters wide and verticalSize lines tall, not
+// counting its walls.  An error is returned if either size is less than one
+func (g *Grid) ToStringSized(horizontalSize, verticalSize int) (string, error) {
 	if (horizontalSize < 1) || (verticalSize < 1) {
-		log.Fatalf("Invalid grid size for toString: [%d, %d]", horizontalSize, verticalSize)
+		return "", fmt.Errorf("invalid cell size for text: [%d, %d]", horizontalSize, verticalSize)
 	}
+	return g.toString(LightWalls, horizontalSize, verticalSize, nil), nil
+}
 
+// toString creates a textual representation of the maze grid with walls drawn
+// in the given style.  Both sizes must be at least one.  If contents is
+// provided, the glyph it returns is drawn in the middle of each cell
+func (g *Grid) toString(style WallStyle, horizontalSize, verticalSize int, contents func(cell *Cell) rune) string {
+	glyphs := style.glyphs()
 	output := ""
 
 	// When drawing a horizontal line across cells, we use several horizontal glyphs in a row
 	horizontalLine := ""
 	horizontalSpace := ""
 	for i := 0; i < horizontalSize; i++ {
-		horizontalLine += string(horizontal)
+		horizontalLine += string(glyphs.horizontal)
 		horizontalSpace += " "
 	}
 
@@ -138,6 +494,7 @@ func (g *Grid) toString(horizontalSize, verticalSize int) string {
 		// Generate the representation of this row
 		topEdge := "" // The horizontal lines between cells
 		area := ""    // The contents of the cells
+		labels := ""  // The contents of the cells, including the glyphs from contents
 		// Loop inclusive of the column count to get the right edge
 		for c := int64(0); c <= g.Columns; c++ {
 			cell := g.At(r, c)
@@ -162,27 +519,26 @@ func (g *Grid) toString(horizontalSize, verticalSize int) string {
 				fmt.Print("}")
 			}
 
-			ul := g.upperLeftCornerGlyph(r, c)
-			topEdge += string(ul)
-			// Ensure that this cell is actually linked to the cell above it.  Otherwise the following shape
-			// would detect a horizontal corner glyph and incorrectly draw the line indicated by XXX
-			// +-------+
-			// |       |
-			// +----XXX|
-			// |       |
-			// +-------+
-			if pointsRight(ul) && (cell == nil || !cell.Linked(cell.North)) {
+			topEdge += string(g.upperLeftCornerGlyph(glyphs, r, c))
+			if g.horizontalWall(r, c) {
 				topEdge += horizontalLine
 			} else {
 				topEdge += horizontalSpace
 			}
 
-			if pointsDown(ul) && (cell == nil || !cell.Linked(cell.West)) {
-				area += string(vertical)
+			leftEdge := " "
+			if g.verticalWall(r, c) {
+				leftEdge = string(glyphs.vertical)
+			}
+			area += leftEdge + horizontalSpace
+			labels += leftEdge
+			if contents != nil && cell != nil {
+				interior := []rune(horizontalSpace)
+				interior[(horizontalSize-1)/2] = contents(cell)
+				labels += string(interior)
 			} else {
-				area += " "
+				labels += horizontalSpace
 			}
-			area += horizontalSpace
 		}
 		if debug {
 			fmt.Print("\n")
@@ -192,7 +548,11 @@ func (g *Grid) toString(horizontalSize, verticalSize int) string {
 		output = output + topEdge + "\n"
 		if r < g.Rows {
 			for i := 0; i < verticalSize; i++ {
-				output = output + area + "\n"
+				if i == (verticalSize-1)/2 {
+					output = output + labels + "\n"
+				} else {
+					output = output + area + "\n"
+				}
 			}
 		}
 	}
@@ -202,43 +562,20 @@ func (g *Grid) toString(horizontalSize, verticalSize int) string {
 
 // upperLeftCornerGlyph returns the glyph which should be shown at the
 // upper-left corner of a cell
-func (g *Grid) upperLeftCornerGlyph(row, column int64) rune {
-	// We care about four cells.  Those cells are located (relative to this glyph)
-	// to the upper-left, upper-right, lower-left, and lower-right.  The row and
-	// column parameters correspond to the cell to the lower-right of the glyph
-	ul := g.At(row-1, column-1)
-	ur := g.At(row-1, column)
-	ll := g.At(row, column-1)
-	lr := g.At(row, column)
-
-	// Determine which directions this glyph needs to face
-	up := false
-	down := false
-	left := false
-	right := false
-
-	if ul != nil {
-		up = (ur == nil) || !ul.Linked(ur)
-		left = (ll == nil) || !ul.Linked(ll)
-	} else {
-		up = (ur != nil)   // If so, we're at the left edge of the grid
-		left = (ll != nil) // If so, we're at the top edge of the grid
-	}
-
-	if lr != nil {
-		down = (ll == nil) || !lr.Linked(ll)
-		right = (ur == nil) || !lr.Linked(ur)
-	} else {
-		down = (ll != nil)
-		right = (ur != nil)
-	}
-
-	return cornerGlyph(up, left, down, right)
+func (g *Grid) upperLeftCornerGlyph(glyphs boxGlyphs, row, column int64) rune {
+	// The glyph extends along each of the four walls which meet at the corner.
+	// The row and column parameters correspond to the cell to the lower-right of
+	// the glyph
+	up := g.verticalWall(row-1, column)
+	down := g.verticalWall(row, column)
+	left := g.horizontalWall(row, column-1)
+	right := g.horizontalWall(row, column)
+	return glyphs.corner(up, left, down, right)
 }
 
-// cornerGlyph returns the glyph appropriate for drawing at a corner
+// corner returns the glyph appropriate for drawing at a corner
 // given the directions it extends into
-func cornerGlyph(up, left, down, right bool) rune {
+func (b boxGlyphs) corner(up, left, down, right bool) rune {
 	// Select the proper glyph given the directions
 	idx := 0
 	if up {
@@ -254,42 +591,22 @@ func cornerGlyph(up, left, down, right bool) rune {
 		idx |= 8
 	}
 	glyphs := [16]rune{
-		' ',             // Nothing
-		vertical,        // Up
-		vertical,        // Down
-		vertical,        // Down | Up
-		horizontal,      // Left
-		cornerUpLeft,    // Left | Up
-		cornerDownLeft,  // Left | Down
-		verticalLeft,    // Left | Down | Up
-		horizontal,      // Right
-		cornerUpRight,   // Right | Up
-		cornerDownRight, // Right | Down
-		verticalRight,   // Right | Down | Up
-		horizontal,      // Right | Left
-		horizontalUp,    // Right | Left | Up
-		horizontalDown,  // Right | Left | Down
-		intersection,    // Right | Left | Down | Up
+		' ',               // Nothing
+		b.vertical,        // Up
+		b.vertical,        // Down
+		b.vertical,        // Down | Up
+		b.horizontal,      // Left
+		b.cornerUpLeft,    // Left | Up
+		b.cornerDownLeft,  // Left | Down
+		b.verticalLeft,    // Left | Down | Up
+		b.horizontal,      // Right
+		b.cornerUpRight,   // Right | Up
+		b.cornerDownRight, // Right | Down
+		b.verticalRight,   // Right | Down | Up
+		b.horizontal,      // Right | Left
+		b.horizontalUp,    // Right | Left | Up
+		b.horizontalDown,  // Right | Left | Down
+		b.intersection,    // Right | Left | Down | Up
 	}
 	return glyphs[idx]
 }
-
-// pointsDown returns true if the provided rune is a box drawing glyph
-// which points down
-func pointsDown(r rune) bool {
-	switch r {
-	case vertical, cornerDownRight, cornerDownLeft, verticalRight, verticalLeft, intersection:
-		return true
-	}
-	return false
-}
-
-// pointsDown returns true if the provided rune is a box drawing glyph
-// which points down
-func pointsRight(r rune) bool {
-	switch r {
-	case horizontal, cornerDownRight, cornerUpRight, verticalRight, horizontalDown, horizontalUp, intersection:
-		return true
-	}
-	return false
-}
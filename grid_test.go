@@ -0,0 +1,390 @@
+package maze
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestToStringSized(t *testing.T) {
+	g := NewGrid(2, 2)
+	g.At(0, 0).Link(g.At(0, 1))
+	g.At(0, 1).Link(g.At(1, 1))
+	tests := []struct {
+		horizontal, vertical int
+		want                 string
+		valid                bool
+	}{
+		{0, 1, "", false},
+		{1, 0, "", false},
+		{-1, -1, "", false},
+		{1, 1, "┌───┐ \n│   │ \n├─┐ │ \n│ │ │ \n└─┴─┘ \n", true},
+		{2, 2, "┌─────┐  \n│     │  \n│     │  \n├──┐  │  \n│  │  │  \n│  │  │  \n└──┴──┘  \n", true},
+		{5, 2, "┌───────────┐     \n" +
+			"│           │     \n" +
+			"│           │     \n" +
+			"├─────┐     │     \n" +
+			"│     │     │     \n" +
+			"│     │     │     \n" +
+			"└─────┴─────┘     \n", true},
+	}
+	for _, tc := range tests {
+		got, err := g.ToStringSized(tc.horizontal, tc.vertical)
+		if !tc.valid {
+			if err == nil {
+				t.Errorf("ToStringSized(%d, %d) returned no error", tc.horizontal, tc.vertical)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("ToStringSized(%d, %d) = %q, %v, want:\n%s", tc.horizontal, tc.vertical, got, err, tc.want)
+		}
+	}
+}
+
+func TestWrappedNeighbors(t *testing.T) {
+	cylinder := NewCylinderGrid(4, 5)
+	torus := NewTorusGrid(4, 5)
+	for r := int64(0); r < 4; r++ {
+		for _, g := range []*Grid{&cylinder, &torus} {
+			if g.At(r, 4).East != g.At(r, 0) || g.At(r, 0).West != g.At(r, 4) {
+				t.Errorf("row %d does not wrap east to west", r)
+			}
+		}
+	}
+	for c := int64(0); c < 5; c++ {
+		if torus.At(3, c).South != torus.At(0, c) || torus.At(0, c).North != torus.At(3, c) {
+			t.Errorf("torus column %d does not wrap south to north", c)
+		}
+		if cylinder.At(3, c).South != nil || cylinder.At(0, c).North != nil {
+			t.Errorf("cylinder column %d wraps south to north", c)
+		}
+	}
+}
+
+func TestWrappedGridTooSmall(t *testing.T) {
+	tests := []struct {
+		name          string
+		new           func(rows, columns int64) (*Grid, error)
+		rows, columns int64
+		ok            bool
+	}{
+		{"CylinderNarrow", NewCylinderGridChecked, 4, 2, false},
+		{"CylinderNoColumns", NewCylinderGridChecked, 4, 0, false},
+		{"CylinderNegative", NewCylinderGridChecked, -1, 3, false},
+		{"CylinderShort", NewCylinderGridChecked, 1, 3, true},
+		{"TorusNarrow", NewTorusGridChecked, 4, 2, false},
+		{"TorusShort", NewTorusGridChecked, 2, 4, false},
+		{"TorusSmallest", NewTorusGridChecked, 3, 3, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g, err := tc.new(tc.rows, tc.columns)
+			if (err == nil) != tc.ok {
+				t.Fatalf("returned error %v, want success %v", err, tc.ok)
+			}
+			if tc.ok && g.At(0, tc.columns-1).East != g.At(0, 0) {
+				t.Errorf("[%d, %d] grid does not wrap east to west", tc.rows, tc.columns)
+			}
+		})
+	}
+}
+
+func TestWrappedGeneratorsArePerfect(t *testing.T) {
+	shapes := []struct {
+		name string
+		grid func() Grid
+	}{
+		{"Cylinder", func() Grid { return NewCylinderGrid(6, 7) }},
+		{"Torus", func() Grid { return NewTorusGrid(6, 7) }},
+	}
+	for _, shape := range shapes {
+		for _, gen := range generators {
+			t.Run(shape.name+"/"+gen.name, func(t *testing.T) {
+				for seed := int64(0); seed < 10; seed++ {
+					g := shape.grid()
+					gen.generate(&g, rand.New(rand.NewSource(seed)))
+					if !IsPerfect(&g) {
+						t.Fatalf("seed %d: maze is not perfect: %d loops, %d regions\n%s",
+							seed, len(Cycles(&g)), RegionCount(&g), g.ToString())
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestCellsMatchAllCells(t *testing.T) {
+	grids := []struct {
+		name string
+		grid *Grid
+	}{
+		{"Rectangle", func() *Grid { g := NewGrid(3, 4); return &g }()},
+		{"Masked", NewMaskedGrid(maskFromString(t, "X...\n..X.\n...X"))},
+	}
+	for _, tc := range grids {
+		t.Run(tc.name, func(t *testing.T) {
+			cells := tc.grid.Cells()
+			i := 0
+			for cell := range tc.grid.AllCells() {
+				if i >= len(cells) || cells[i] != cell {
+					t.Fatalf("AllCells and Cells differ at index %d", i)
+				}
+				i++
+			}
+			if i != len(cells) || int64(i) != tc.grid.Size() {
+				t.Errorf("AllCells returned %d cells and Cells %d, want %d", i, len(cells), tc.grid.Size())
+			}
+			rows := tc.grid.CellRows()
+			r := 0
+			for row := range tc.grid.AllRows() {
+				for c := range row {
+					if rows[r][c] != row[c] {
+						t.Fatalf("AllRows and CellRows differ at [%d, %d]", r, c)
+					}
+				}
+				r++
+			}
+			if r != len(rows) {
+				t.Errorf("AllRows returned %d rows and CellRows %d", r, len(rows))
+			}
+		})
+	}
+}
+
+func BenchmarkAllCells(b *testing.B) {
+	g := NewGrid(64, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for cell := range g.AllCells() {
+			_ = cell
+		}
+	}
+}
+
+func BenchmarkCells(b *testing.B) {
+	g := NewGrid(64, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, cell := range g.Cells() {
+			_ = cell
+		}
+	}
+}
+
+func BenchmarkAllRows(b *testing.B) {
+	g := NewGrid(64, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for row := range g.AllRows() {
+			_ = row
+		}
+	}
+}
+
+func BenchmarkCellRows(b *testing.B) {
+	g := NewGrid(64, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, row := range g.CellRows() {
+			_ = row
+		}
+	}
+}
+
+func TestNewGridChecked(t *testing.T) {
+	tests := []struct {
+		rows, columns int64
+		valid         bool
+	}{
+		{-1, 5, false},
+		{5, -1, false},
+		{-1, -1, false},
+		{0, 0, true},
+		{3, 4, true},
+	}
+	for _, tc := range tests {
+		g, err := NewGridChecked(tc.rows, tc.columns)
+		if !tc.valid {
+			if err == nil {
+				t.Errorf("NewGridChecked(%d, %d) returned no error", tc.rows, tc.columns)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("NewGridChecked(%d, %d) returned %v", tc.rows, tc.columns, err)
+			continue
+		}
+		if g.Rows != tc.rows || g.Columns != tc.columns || g.Size() != tc.rows*tc.columns {
+			t.Errorf("NewGridChecked(%d, %d) built a [%d, %d] grid of %d cells", tc.rows, tc.columns, g.Rows, g.Columns, g.Size())
+		}
+	}
+}
+
+func TestEqual(t *testing.T) {
+	maze := func(seed int64) *Grid {
+		g := NewGrid(5, 6)
+		RecursiveBacktrackerRand(&g, rand.New(rand.NewSource(seed)))
+		return &g
+	}
+	extraLink := maze(1)
+	for _, n := range extraLink.At(2, 2).Neighbors() {
+		if !n.Linked(extraLink.At(2, 2)) {
+			n.Link(extraLink.At(2, 2))
+			break
+		}
+	}
+	reversed := func() *Grid {
+		g := NewGrid(1, 3)
+		g.At(0, 2).Link(g.At(0, 1))
+		g.At(0, 1).Link(g.At(0, 0))
+		return &g
+	}
+	forward := func() *Grid {
+		g := NewGrid(1, 3)
+		g.At(0, 0).Link(g.At(0, 1))
+		g.At(0, 1).Link(g.At(0, 2))
+		return &g
+	}
+	tunnel := func() *Grid {
+		g := NewGrid(1, 3)
+		g.At(0, 0).Link(g.At(0, 2))
+		return &g
+	}
+	tests := []struct {
+		name string
+		a, b *Grid
+		want bool
+	}{
+		{"SameSeed", maze(1), maze(1), true},
+		{"Clone", maze(1), maze(1).Clone(), true},
+		{"DifferentSeed", maze(1), maze(2), false},
+		{"DifferentDimensions", func() *Grid { g := NewGrid(2, 3); return &g }(), func() *Grid { g := NewGrid(3, 2); return &g }(), false},
+		{"ExtraLink", maze(1), extraLink, false},
+		{"LinkOrder", forward(), reversed(), true},
+		{"NonNeighborLink", tunnel(), tunnel(), true},
+		{"NonNeighborMissing", tunnel(), func() *Grid { g := NewGrid(1, 3); return &g }(), false},
+		{"Masked", NewMaskedGrid(maskFromString(t, "X..")), func() *Grid { g := NewGrid(1, 3); return &g }(), false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Equal(tc.a, tc.b); got != tc.want {
+				t.Errorf("Equal() = %v, want %v", got, tc.want)
+			}
+			if got := Equal(tc.b, tc.a); got != tc.want {
+				t.Errorf("Equal() with the grids swapped = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestToStringStyle(t *testing.T) {
+	unlinked := NewGrid(2, 2)
+	// A maze shaped like a U, open along the top, right, and bottom
+	u := NewGrid(2, 2)
+	u.At(0, 0).Link(u.At(0, 1))
+	u.At(0, 1).Link(u.At(1, 1))
+	u.At(1, 1).Link(u.At(1, 0))
+	tests := []struct {
+		name  string
+		grid  *Grid
+		style WallStyle
+		want  string
+	}{
+		{"Light", &unlinked, LightWalls, "┌───┬───┐   \n│   │   │   \n├───┼───┤   \n│   │   │   \n└───┴───┘   \n"},
+		{"Heavy", &unlinked, HeavyWalls, "┏━━━┳━━━┓   \n┃   ┃   ┃   \n┣━━━╋━━━┫   \n┃   ┃   ┃   \n┗━━━┻━━━┛   \n"},
+		{"Double", &unlinked, DoubleWalls, "╔═══╦═══╗   \n║   ║   ║   \n╠═══╬═══╣   \n║   ║   ║   \n╚═══╩═══╝   \n"},
+		{"ASCII", &unlinked, ASCIIWalls, "+---+---+   \n|   |   |   \n+---+---+   \n|   |   |   \n+---+---+   \n"},
+		{"LightU", &u, LightWalls, "┌───────┐   \n│       │   \n├────   │   \n│       │   \n└───────┘   \n"},
+		{"HeavyU", &u, HeavyWalls, "┏━━━━━━━┓   \n┃       ┃   \n┣━━━━   ┃   \n┃       ┃   \n┗━━━━━━━┛   \n"},
+		{"DoubleU", &u, DoubleWalls, "╔═══════╗   \n║       ║   \n╠════   ║   \n║       ║   \n╚═══════╝   \n"},
+		{"ASCIIU", &u, ASCIIWalls, "+-------+   \n|       |   \n+----   |   \n|       |   \n+-------+   \n"},
+		{"UnknownStyle", &u, WallStyle(99), "┌───────┐   \n│       │   \n├────   │   \n│       │   \n└───────┘   \n"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.grid.ToStringStyle(tc.style); got != tc.want {
+				t.Errorf("ToStringStyle() =\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+	if got, want := u.ToString(), u.ToStringStyle(LightWalls); got != want {
+		t.Errorf("ToString() =\n%s\nwant the light style:\n%s", got, want)
+	}
+}
+
+func TestClone(t *testing.T) {
+	shapes := []struct {
+		name string
+		grid func() *Grid
+	}{
+		{"Rectangle", func() *Grid { g := NewGrid(4, 5); return &g }},
+		{"Masked", func() *Grid { return NewMaskedGrid(maskFromString(t, ".....\n.XX..\n....X\n.....")) }},
+		{"Cylinder", func() *Grid { g := NewCylinderGrid(4, 5); return &g }},
+		{"Torus", func() *Grid { g := NewTorusGrid(4, 5); return &g }},
+		{"Openings", func() *Grid {
+			g := NewGrid(4, 5)
+			g.OpenBorder(g.At(0, 0), West)
+			g.OpenBorder(g.At(3, 4), South)
+			return &g
+		}},
+	}
+	for _, tc := range shapes {
+		t.Run(tc.name, func(t *testing.T) {
+			g := tc.grid()
+			RecursiveBacktrackerRand(g, rand.New(rand.NewSource(1)))
+			clone := g.Clone()
+			if !Equal(g, clone) || clone.ToString() != g.ToString() {
+				t.Fatalf("Clone() =\n%s\nwant:\n%s", clone.ToString(), g.ToString())
+			}
+			for _, cell := range clone.Cells() {
+				for _, n := range append(cell.Neighbors(), cell.Links()...) {
+					if n != clone.At(n.Row, n.Column) {
+						t.Fatalf("cloned cell [%d, %d] leads to a cell of the original grid", cell.Row, cell.Column)
+					}
+				}
+			}
+
+			// Link a pair in the clone and unlink a pair in the original
+			var walled, open [2]*Cell
+			ForEachAdjacentPair(g, func(a, b *Cell) {
+				if a.Linked(b) {
+					open = [2]*Cell{a, b}
+				} else {
+					walled = [2]*Cell{a, b}
+				}
+			})
+			clone.At(walled[0].Row, walled[0].Column).Link(clone.At(walled[1].Row, walled[1].Column))
+			open[0].Unlink(open[1])
+			if walled[0].Linked(walled[1]) || walled[1].Linked(walled[0]) {
+				t.Error("linking cells in the clone linked them in the original")
+			}
+			if !clone.At(open[0].Row, open[0].Column).Linked(clone.At(open[1].Row, open[1].Column)) {
+				t.Error("unlinking cells in the original unlinked them in the clone")
+			}
+		})
+	}
+}
+
+func TestReset(t *testing.T) {
+	for _, gen := range generators {
+		t.Run(gen.name, func(t *testing.T) {
+			g := NewGrid(5, 6)
+			gen.generate(&g, rand.New(rand.NewSource(1)))
+			g.Reset()
+			for _, cell := range g.Cells() {
+				if cell.hasLinks() {
+					t.Errorf("cell [%d, %d] is still linked to %v", cell.Row, cell.Column, positions(cell.Links()))
+				}
+			}
+			walled := NewGrid(5, 6)
+			if g.ToString() != walled.ToString() {
+				t.Errorf("ToString() after Reset =\n%s\nwant:\n%s", g.ToString(), walled.ToString())
+			}
+			// The grid can be used again
+			gen.generate(&g, rand.New(rand.NewSource(2)))
+			if !IsPerfect(&g) {
+				t.Errorf("maze generated after Reset is not perfect:\n%s", g.ToString())
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package maze
+
+import (
+	"math/rand"
+)
+
+// GrowingTree uses the growing tree maze creation algorithm to create a maze in
+// a rectangular grid.  It keeps a list of active cells, beginning with a random
+// cell.  On each step pick chooses an active cell, which is linked to a random
+// unvisited neighbor that then becomes active.  Cells without unvisited
+// neighbors are removed from the list.  The strategy used by pick determines
+// the texture of the maze: PickNewest behaves like the recursive backtracker,
+// while PickRandom behaves like Prim's algorithm
+func GrowingTree(g *Grid, pick func(active []*Cell) int) {
+	GrowingTreeRand(g, pick, defaultRand)
+}
+
+// GrowingTreeRand is GrowingTree using the provided random source.  Use
+// PickRandomFrom rather than PickRandom for results which depend only on r
+func GrowingTreeRand(g *Grid, pick func(active []*Cell) int, r *rand.Rand) {
+	if g.Size() == 0 {
+		return
+	}
+	start := g.RandomCellRand(r)
+	active := []*Cell{start}
+	visited := map[*Cell]bool{start: true}
+	for len(active) > 0 {
+		i := pick(active)
+		cell := active[i]
+		unvisited := []*Cell{}
+		for _, n := range cell.Neighbors() {
+			if !visited[n] {
+				unvisited = append(unvisited, n)
+			}
+		}
+
+		if len(unvisited) == 0 {
+			active = append(active[:i], active[i+1:]...)
+			continue
+		}
+		next := unvisited[r.Intn(len(unvisited))]
+		cell.Link(next)
+		visited[next] = true
+		active = append(active, next)
+	}
+}
+
+// PickNewest chooses the most recently added active cell
+func PickNewest(active []*Cell) int {
+	return len(active) - 1
+}
+
+// PickRandom chooses a random active cell
+func PickRandom(active []*Cell) int {
+	return rand.Intn(len(active))
+}
+
+// PickRandomFrom returns a strategy which chooses a random active cell using the
+// provided random source
+func PickRandomFrom(r *rand.Rand) func(active []*Cell) int {
+	return func(active []*Cell) int {
+		return r.Intn(len(active))
+	}
+}
@@ -0,0 +1,58 @@
+package maze
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestPickNewest(t *testing.T) {
+	g := NewGrid(1, 3)
+	for n := 1; n <= 3; n++ {
+		if got := PickNewest(g.Cells()[:n]); got != n-1 {
+			t.Errorf("PickNewest() of %d cells = %d, want %d", n, got, n-1)
+		}
+	}
+}
+
+func TestGrowingTree(t *testing.T) {
+	strategies := []struct {
+		name string
+		pick func(r *rand.Rand) func(active []*Cell) int
+	}{
+		{"Newest", func(r *rand.Rand) func([]*Cell) int { return PickNewest }},
+		{"Random", func(r *rand.Rand) func([]*Cell) int { return PickRandom }},
+		{"RandomFrom", PickRandomFrom},
+		{"Oldest", func(r *rand.Rand) func([]*Cell) int { return func([]*Cell) int { return 0 } }},
+	}
+	for _, s := range strategies {
+		t.Run(s.name, func(t *testing.T) {
+			for seed := int64(0); seed < 5; seed++ {
+				r := rand.New(rand.NewSource(seed))
+				g := NewGrid(9, 11)
+				GrowingTreeRand(&g, func(active []*Cell) int {
+					if len(active) == 0 {
+						t.Fatal("pick was called with no active cells")
+					}
+					return s.pick(r)(active)
+				}, r)
+				if !IsPerfect(&g) {
+					t.Errorf("seed %d: maze is not perfect:\n%s", seed, g.ToString())
+				}
+			}
+		})
+	}
+}
+
+func TestGrowingTreePickNewestIsBacktracker(t *testing.T) {
+	// Always continuing from the newest cell is a depth-first walk, so it makes
+	// the same choices as the recursive backtracker
+	for seed := int64(0); seed < 5; seed++ {
+		g := NewGrid(8, 8)
+		GrowingTreeRand(&g, PickNewest, rand.New(rand.NewSource(seed)))
+		want := NewGrid(8, 8)
+		RecursiveBacktrackerRand(&want, rand.New(rand.NewSource(seed)))
+		if !Equal(&g, &want) {
+			t.Errorf("seed %d: GrowingTree(PickNewest) =\n%s\nwant:\n%s", seed, g.ToString(), want.ToString())
+		}
+	}
+}
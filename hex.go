@@ -0,0 +1,233 @@
+package maze
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"math/rand"
+)
+
+// HexCell represents a hexagonal cell in a maze.  Cells are pointy-topped, so
+// each has neighbors to the east and west plus two above and two below
+type HexCell struct {
+	// The location of this cell in the grid
+	Row, Column int64
+	// The neighbors of this cell
+	NorthEast, East, SouthEast, SouthWest, West, NorthWest *HexCell
+	// The cells directly linked to this cell
+	links nodeLinks
+}
+
+var _ Node = (*HexCell)(nil)
+var _ Graph = (*HexGrid)(nil)
+
+// Link links one cell to another bidirectionally
+func (c *HexCell) Link(neighbor *HexCell) {
+	c.links.add(neighbor)
+	neighbor.links.add(c)
+}
+
+// Unlink removes the bidirectional link between two cells
+func (c *HexCell) Unlink(neighbor *HexCell) {
+	c.links.remove(neighbor)
+	neighbor.links.remove(c)
+}
+
+// Linked returns true if a cell is linked to another
+func (c *HexCell) Linked(neighbor *HexCell) bool {
+	return neighbor != nil && c.links.has(neighbor)
+}
+
+// LinkNode links this cell to another bidirectionally.  The other cell must be a
+// *HexCell
+func (c *HexCell) LinkNode(neighbor Node) {
+	c.Link(neighbor.(*HexCell))
+}
+
+// LinkedNodes returns the cells this cell is linked to, in the order the links
+// were made
+func (c *HexCell) LinkedNodes() []Node {
+	return c.links.nodes()
+}
+
+// NeighborNodes returns the direct neighbors of this cell, in the same order as
+// Neighbors
+func (c *HexCell) NeighborNodes() []Node {
+	ret := []Node{}
+	for _, n := range c.Neighbors() {
+		ret = append(ret, n)
+	}
+	return ret
+}
+
+// Neighbors returns the list of direct neighbors of this cell, clockwise from
+// the northeast
+func (c *HexCell) Neighbors() []*HexCell {
+	ret := []*HexCell{}
+	for _, n := range []*HexCell{c.NorthEast, c.East, c.SouthEast, c.SouthWest, c.West, c.NorthWest} {
+		if n != nil {
+			ret = append(ret, n)
+		}
+	}
+	return ret
+}
+
+// HexGrid represents a maze of hexagonal cells.  Rows are offset so that every
+// odd row is shifted half a cell to the east of the rows around it
+type HexGrid struct {
+	// Rows and Columns indicate the size of the grid
+	Rows, Columns int64
+	// The cells in the grid
+	grid [][]*HexCell
+}
+
+// NewHexGrid creates a new hexagonal grid.  An error is returned if either
+// dimension is negative
+func NewHexGrid(rows, columns int64) (*HexGrid, error) {
+	if rows < 0 || columns < 0 {
+		return nil, fmt.Errorf("hex grid dimensions invalid: [%d, %d]", rows, columns)
+	}
+	g := &HexGrid{
+		Rows:    rows,
+		Columns: columns,
+		grid:    make([][]*HexCell, rows)}
+	g.prepareGrid()
+	g.configureCells()
+	return g, nil
+}
+
+// prepareGrid creates the cells in the grid
+func (g *HexGrid) prepareGrid() {
+	for r := int64(0); r < g.Rows; r++ {
+		g.grid[r] = make([]*HexCell, g.Columns)
+		for c := int64(0); c < g.Columns; c++ {
+			g.grid[r][c] = &HexCell{Row: r, Column: c}
+		}
+	}
+}
+
+// configureCells establishes the neighbors of each cell
+func (g *HexGrid) configureCells() {
+	for _, cell := range g.Cells() {
+		r, c := cell.Row, cell.Column
+		// The columns of the diagonal neighbors depend on which way this row is shifted
+		west, east := c-1, c
+		if r%2 == 1 {
+			west, east = c, c+1
+		}
+		cell.NorthEast = g.At(r-1, east)
+		cell.East = g.At(r, c+1)
+		cell.SouthEast = g.At(r+1, east)
+		cell.SouthWest = g.At(r+1, west)
+		cell.West = g.At(r, c-1)
+		cell.NorthWest = g.At(r-1, west)
+	}
+}
+
+// At accesses a cell from the grid
+func (g *HexGrid) At(row, column int64) *HexCell {
+	if row < 0 || column < 0 || row >= g.Rows || column >= g.Columns {
+		return nil
+	}
+	return g.grid[row][column]
+}
+
+// AllCells iterates over all of the cells in the grid
+func (g *HexGrid) AllCells() <-chan *HexCell {
+	c := make(chan *HexCell)
+	go func() {
+		for _, cell := range g.Cells() {
+			c <- cell
+		}
+		close(c)
+	}()
+	return c
+}
+
+// Cells returns all of the cells in the grid in row-major order
+func (g *HexGrid) Cells() []*HexCell {
+	cells := make([]*HexCell, 0, g.Size())
+	for _, row := range g.grid {
+		cells = append(cells, row...)
+	}
+	return cells
+}
+
+// Nodes returns all of the cells in the grid in row-major order
+func (g *HexGrid) Nodes() []Node {
+	ret := []Node{}
+	for _, cell := range g.Cells() {
+		ret = append(ret, cell)
+	}
+	return ret
+}
+
+// RandomCell returns a random cell from the grid
+func (g *HexGrid) RandomCell() *HexCell {
+	return g.RandomCellRand(defaultRand)
+}
+
+// RandomCellRand returns a random cell from the grid chosen using the provided
+// random source.  If the grid has no cells, it returns nil
+func (g *HexGrid) RandomCellRand(r *rand.Rand) *HexCell {
+	if g.Size() == 0 {
+		return nil
+	}
+	return g.At(r.Int63n(g.Rows), r.Int63n(g.Columns))
+}
+
+// RandomNode returns a random cell from the grid chosen using the provided
+// random source, or nil if the grid has no cells
+func (g *HexGrid) RandomNode(r *rand.Rand) Node {
+	if c := g.RandomCellRand(r); c != nil {
+		return c
+	}
+	return nil
+}
+
+// Size returns the number of cells in the grid
+func (g *HexGrid) Size() int64 {
+	return g.Rows * g.Columns
+}
+
+// ToPNG renders the maze as a PNG image.  cellSize is the distance in pixels
+// from the center of each hexagon to its corners
+func (g *HexGrid) ToPNG(w io.Writer, cellSize int) error {
+	if cellSize < 1 {
+		return fmt.Errorf("invalid PNG cell size: %d", cellSize)
+	}
+
+	size := float64(cellSize)
+	width := math.Sqrt(3) * size
+	imgWidth := int(math.Ceil(width*(float64(g.Columns)+0.5))) + 1
+	imgHeight := int(math.Ceil(1.5*size*float64(g.Rows)+0.5*size)) + 1
+	img := image.NewRGBA(image.Rect(0, 0, imgWidth, imgHeight))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: background}, image.Point{}, draw.Src)
+
+	for _, cell := range g.Cells() {
+		cx := width * (float64(cell.Column) + 0.5)
+		if cell.Row%2 == 1 {
+			cx += width / 2
+		}
+		cy := 1.5*size*float64(cell.Row) + size
+
+		// The corners of the hexagon, clockwise from the top
+		corners := [6][2]int{}
+		for i := range corners {
+			theta := math.Pi/3*float64(i) - math.Pi/2
+			corners[i] = [2]int{int(math.Round(cx + size*math.Cos(theta))), int(math.Round(cy + size*math.Sin(theta)))}
+		}
+		// Each side lies between the corners on either side of it
+		for i, n := range []*HexCell{cell.NorthEast, cell.East, cell.SouthEast, cell.SouthWest, cell.West, cell.NorthWest} {
+			if !cell.Linked(n) {
+				from, to := corners[i], corners[(i+1)%6]
+				drawLine(img, from[0], from[1], to[0], to[1], wallColor)
+			}
+		}
+	}
+
+	return png.Encode(w, img)
+}
@@ -0,0 +1,138 @@
+package maze
+
+import (
+	"bytes"
+	"image/png"
+	"math/rand"
+	"testing"
+)
+
+func TestHexNeighbors(t *testing.T) {
+	g, err := NewHexGrid(8, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Neighbors are listed clockwise from the northeast
+	tests := []struct {
+		name      string
+		cell      [2]int64
+		neighbors [6][2]int64
+	}{
+		{"EvenRow", [2]int64{2, 3}, [6][2]int64{{1, 3}, {2, 4}, {3, 3}, {3, 2}, {2, 2}, {1, 2}}},
+		{"OddRow", [2]int64{3, 3}, [6][2]int64{{2, 4}, {3, 4}, {4, 4}, {4, 3}, {3, 2}, {2, 3}}},
+		{"Corner", [2]int64{0, 0}, [6][2]int64{{-1, -1}, {0, 1}, {1, 0}, {-1, -1}, {-1, -1}, {-1, -1}}},
+		{"OddRowEastEdge", [2]int64{1, 9}, [6][2]int64{{-1, -1}, {-1, -1}, {-1, -1}, {2, 9}, {1, 8}, {0, 9}}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := g.At(tc.cell[0], tc.cell[1])
+			got := []*HexCell{c.NorthEast, c.East, c.SouthEast, c.SouthWest, c.West, c.NorthWest}
+			for i, want := range tc.neighbors {
+				if got[i] != g.At(want[0], want[1]) {
+					t.Errorf("neighbor %d = %v, want %v", i, got[i], want)
+				}
+			}
+		})
+	}
+
+	for _, cell := range g.Cells() {
+		interior := cell.Row > 0 && cell.Row < g.Rows-1 && cell.Column > 0 && cell.Column < g.Columns-1
+		if interior && len(cell.Neighbors()) != 6 {
+			t.Errorf("interior cell [%d, %d] has %d neighbors", cell.Row, cell.Column, len(cell.Neighbors()))
+		}
+		for _, n := range cell.Neighbors() {
+			if !containsHexCell(n.Neighbors(), cell) {
+				t.Errorf("cell [%d, %d] isn't a neighbor of its neighbor [%d, %d]", cell.Row, cell.Column, n.Row, n.Column)
+			}
+		}
+	}
+}
+
+// containsHexCell returns true if a cell appears in a list of cells
+func containsHexCell(cells []*HexCell, c *HexCell) bool {
+	for _, x := range cells {
+		if x == c {
+			return true
+		}
+	}
+	return false
+}
+
+func TestNewHexGrid(t *testing.T) {
+	tests := []struct {
+		rows, columns int64
+		valid         bool
+	}{
+		{0, 0, true},
+		{3, 4, true},
+		{-1, 4, false},
+		{3, -1, false},
+	}
+	for _, tc := range tests {
+		g, err := NewHexGrid(tc.rows, tc.columns)
+		if (err == nil) != tc.valid {
+			t.Errorf("NewHexGrid(%d, %d) returned %v", tc.rows, tc.columns, err)
+			continue
+		}
+		if tc.valid && (g.Size() != tc.rows*tc.columns || int64(len(g.Nodes())) != g.Size()) {
+			t.Errorf("NewHexGrid(%d, %d) has %d cells", tc.rows, tc.columns, g.Size())
+		}
+	}
+}
+
+func TestHexLink(t *testing.T) {
+	g, err := NewHexGrid(2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := g.At(0, 0), g.At(1, 0)
+	a.Link(b)
+	if !a.Linked(b) || !b.Linked(a) || a.Linked(nil) {
+		t.Error("Link() didn't link the cells in both directions")
+	}
+	b.Unlink(a)
+	if a.Linked(b) || b.Linked(a) {
+		t.Error("Unlink() didn't unlink the cells in both directions")
+	}
+}
+
+func TestHexMaze(t *testing.T) {
+	for seed := int64(0); seed < 5; seed++ {
+		g, err := NewHexGrid(8, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		RecursiveBacktrackerGraph(g, rand.New(rand.NewSource(seed)))
+		if !graphIsPerfect(g) {
+			t.Fatalf("seed %d: maze is not perfect", seed)
+		}
+		if d := GraphDistances(g.At(0, 0)); len(d) != int(g.Size()) {
+			t.Fatalf("seed %d: GraphDistances() reached %d cells, want %d", seed, len(d), g.Size())
+		}
+
+		var out bytes.Buffer
+		if err := g.ToPNG(&out, 12); err != nil {
+			t.Fatal(err)
+		}
+		img, err := png.Decode(&out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if size := img.Bounds().Size(); size.X != 220 || size.Y != 151 {
+			t.Errorf("image is %v, want 220x151", size)
+		}
+	}
+}
+
+func TestHexRandomCell(t *testing.T) {
+	empty, err := NewHexGrid(0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := empty.RandomCellRand(rand.New(rand.NewSource(1))); c != nil {
+		t.Errorf("RandomCellRand() of an empty grid = [%d, %d], want nil", c.Row, c.Column)
+	}
+	if n := empty.RandomNode(rand.New(rand.NewSource(1))); n != nil {
+		t.Errorf("RandomNode() of an empty grid = %v, want nil", n)
+	}
+}
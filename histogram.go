@@ -0,0 +1,115 @@
+package maze
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+)
+
+// degreeHistogram counts the cells of the grid by the number of passages
+// leading out of them
+func degreeHistogram(g *Grid) [5]int {
+	var hist [5]int
+	for _, cell := range g.Cells() {
+		hist[cell.linkCount()]++
+	}
+	return hist
+}
+
+// histogramDistance measures how far one histogram is from another
+func histogramDistance(a, b [5]int) int {
+	dist := 0
+	for i := range a {
+		if a[i] > b[i] {
+			dist += a[i] - b[i]
+		} else {
+			dist += b[i] - a[i]
+		}
+	}
+	return dist
+}
+
+// GenerateToHistogram generates a maze whose cells approximate the requested
+// histogram of passage counts, where target[i] is the number of cells with i
+// passages.  It begins with a perfect maze and repeatedly braids (adds) or thins
+// (removes) passages, keeping every change which doesn't move the maze away from
+// the target.  Thinning never disconnects the maze.  After a bounded number of
+// attempts it gives up and leaves the closest maze it found in the grid.  The
+// histogram actually achieved is returned
+func GenerateToHistogram(g *Grid, target [5]int, r *rand.Rand) ([5]int, error) {
+	total := 0
+	for i, count := range target {
+		if count < 0 {
+			return [5]int{}, fmt.Errorf("histogram entry %d is negative", i)
+		}
+		total += count
+	}
+	if int64(total) != g.Size() {
+		return [5]int{}, fmt.Errorf("histogram describes %d cells but the grid has %d", total, g.Size())
+	}
+	if g.Size() == 0 {
+		return target, nil
+	}
+	if r == nil {
+		return [5]int{}, errors.New("a random source is required")
+	}
+
+	g.Reset()
+	recursiveBacktracker(g, g.RandomCellRand(r), r, nil)
+
+	pairs := [][2]*Cell{}
+	ForEachAdjacentPair(g, func(a, b *Cell) {
+		pairs = append(pairs, [2]*Cell{a, b})
+	})
+	if len(pairs) == 0 {
+		return degreeHistogram(g), nil
+	}
+
+	// Each passage contributes to the passage count of two cells
+	targetLinks := 0
+	for i, count := range target {
+		targetLinks += i * count
+	}
+	targetLinks /= 2
+
+	hist := degreeHistogram(g)
+	links := int(g.Size() - 1)
+	for i := 0; i < 10*len(pairs) && histogramDistance(hist, target) > 0; i++ {
+		pair := pairs[r.Intn(len(pairs))]
+		a, b := pair[0], pair[1]
+		braid := !a.Linked(b)
+		// Prefer changes which move the number of passages toward the target
+		if (braid && links > targetLinks) || (!braid && links < targetLinks) {
+			continue
+		}
+
+		next := hist
+		da, db := a.linkCount(), b.linkCount()
+		next[da]--
+		next[db]--
+		if braid {
+			next[da+1]++
+			next[db+1]++
+		} else {
+			next[da-1]++
+			next[db-1]++
+		}
+		if histogramDistance(next, target) > histogramDistance(hist, target) {
+			continue
+		}
+
+		if braid {
+			a.Link(b)
+			links++
+		} else {
+			a.Unlink(b)
+			if !Reachable(a)[b] {
+				a.Link(b)
+				continue
+			}
+			links--
+		}
+		hist = next
+	}
+	return hist, nil
+}
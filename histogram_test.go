@@ -0,0 +1,64 @@
+package maze
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestGenerateToHistogram(t *testing.T) {
+	tests := []struct {
+		name   string
+		rows   int64
+		cols   int64
+		target [5]int
+		exact  bool
+	}{
+		{"Loop", 2, 2, [5]int{0, 0, 4, 0, 0}, true},
+		{"Corridor", 1, 4, [5]int{0, 2, 2, 0, 0}, true},
+		{"FullyLinked", 3, 3, [5]int{0, 0, 4, 4, 1}, true},
+		{"ManyDeadEnds", 6, 6, [5]int{0, 18, 6, 12, 0}, false},
+		{"Unreachable", 3, 3, [5]int{9, 0, 0, 0, 0}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for seed := int64(0); seed < 5; seed++ {
+				g := NewGrid(tc.rows, tc.cols)
+				hist, err := GenerateToHistogram(&g, tc.target, rand.New(rand.NewSource(seed)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if hist != degreeHistogram(&g) {
+					t.Fatalf("seed %d: returned %v, but the maze has %v", seed, hist, degreeHistogram(&g))
+				}
+				if tc.exact && hist != tc.target {
+					t.Errorf("seed %d: achieved %v, want %v", seed, hist, tc.target)
+				}
+				if RegionCount(&g) != 1 {
+					t.Errorf("seed %d: maze has %d regions", seed, RegionCount(&g))
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateToHistogramErrors(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	tests := []struct {
+		name   string
+		target [5]int
+		r      *rand.Rand
+	}{
+		{"Negative", [5]int{5, -1, 0, 0, 0}, r},
+		{"TooFewCells", [5]int{0, 2, 1, 0, 0}, r},
+		{"TooManyCells", [5]int{0, 2, 3, 0, 0}, r},
+		{"NoRandomSource", [5]int{0, 2, 2, 0, 0}, nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGrid(2, 2)
+			if _, err := GenerateToHistogram(&g, tc.target, tc.r); err == nil {
+				t.Error("returned no error")
+			}
+		})
+	}
+}
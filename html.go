@@ -0,0 +1,56 @@
+package maze
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// htmlStyle draws the walls marked by the classes ToHTML assigns to each cell
+const htmlStyle = `<style>
+table.maze { border-collapse: collapse; }
+table.maze td { width: 20px; height: 20px; padding: 0; border: 2px solid transparent; }
+table.maze td.wall-n { border-top-color: black; }
+table.maze td.wall-s { border-bottom-color: black; }
+table.maze td.wall-e { border-right-color: black; }
+table.maze td.wall-w { border-left-color: black; }
+table.maze td.masked { background: gray; }
+</style>
+`
+
+// ToHTML writes the maze as an HTML table with one <td> per cell, preceded by a
+// <style> block which draws the walls.  Each cell is given the classes wall-n,
+// wall-s, wall-e, and wall-w for the sides on which a wall stands, and cells
+// which a mask disables are given the class masked, so the maze can be styled
+// entirely in CSS
+func (g *Grid) ToHTML(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString(htmlStyle)
+	sb.WriteString("<table class=\"maze\">\n")
+	for row := int64(0); row < g.Rows; row++ {
+		sb.WriteString("<tr>")
+		for col := int64(0); col < g.Columns; col++ {
+			classes := []string{}
+			if g.horizontalWall(row, col) {
+				classes = append(classes, "wall-n")
+			}
+			if g.verticalWall(row, col+1) {
+				classes = append(classes, "wall-e")
+			}
+			if g.horizontalWall(row+1, col) {
+				classes = append(classes, "wall-s")
+			}
+			if g.verticalWall(row, col) {
+				classes = append(classes, "wall-w")
+			}
+			if g.At(row, col) == nil {
+				classes = append(classes, "masked")
+			}
+			fmt.Fprintf(&sb, "<td class=\"%s\"></td>", strings.Join(classes, " "))
+		}
+		sb.WriteString("</tr>\n")
+	}
+	sb.WriteString("</table>\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
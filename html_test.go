@@ -0,0 +1,39 @@
+package maze
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestToHTML(t *testing.T) {
+	masked := NewMaskedGrid(maskFromString(t, ".X\n.."))
+	masked.At(0, 0).Link(masked.At(1, 0))
+	tests := []struct {
+		name string
+		grid *Grid
+		want string
+	}{
+		{"Serpentine", serpentine(2, 2), "<table class=\"maze\">\n" +
+			"<tr><td class=\"wall-n wall-s wall-w\"></td><td class=\"wall-n wall-e\"></td></tr>\n" +
+			"<tr><td class=\"wall-n wall-s wall-w\"></td><td class=\"wall-e wall-s\"></td></tr>\n" +
+			"</table>\n"},
+		// Only the walls of enabled cells are drawn, so the disabled cell has
+		// no walls on the outer edges of the grid
+		{"Masked", masked, "<table class=\"maze\">\n" +
+			"<tr><td class=\"wall-n wall-e wall-w\"></td><td class=\"wall-s wall-w masked\"></td></tr>\n" +
+			"<tr><td class=\"wall-e wall-s wall-w\"></td><td class=\"wall-n wall-e wall-s wall-w\"></td></tr>\n" +
+			"</table>\n"},
+		{"Empty", linkedGrid(0, 0, nil), "<table class=\"maze\">\n</table>\n"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := tc.grid.ToHTML(&out); err != nil {
+				t.Fatal(err)
+			}
+			if want := htmlStyle + tc.want; out.String() != want {
+				t.Errorf("ToHTML() =\n%s\nwant:\n%s", out.String(), want)
+			}
+		})
+	}
+}
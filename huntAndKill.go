@@ -0,0 +1,64 @@
+package maze
+
+import (
+	"math/rand"
+)
+
+// HuntAndKill uses the hunt-and-kill maze creation algorithm to create a maze in
+// a rectangular grid.  It carves a random walk through unvisited cells until it
+// reaches a dead end, then scans the grid row by row for the first unvisited
+// cell beside a visited one, links the two, and resumes walking from there
+func HuntAndKill(g *Grid) {
+	HuntAndKillRand(g, defaultRand)
+}
+
+// HuntAndKillRand is HuntAndKill using the provided random source
+func HuntAndKillRand(g *Grid, r *rand.Rand) {
+	if g.Size() == 0 {
+		return
+	}
+	current := g.RandomCellRand(r)
+	visited := map[*Cell]bool{current: true}
+	for current != nil {
+		unvisited := []*Cell{}
+		for _, n := range current.Neighbors() {
+			if !visited[n] {
+				unvisited = append(unvisited, n)
+			}
+		}
+
+		if len(unvisited) > 0 {
+			next := unvisited[r.Intn(len(unvisited))]
+			current.Link(next)
+			visited[next] = true
+			current = next
+		} else {
+			current = hunt(g, visited, r)
+		}
+	}
+}
+
+// hunt finds the first unvisited cell, in row-major order, which has a visited
+// neighbor.  It links that cell to one of its visited neighbors, marks it
+// visited, and returns it.  If there is no such cell, it returns nil
+func hunt(g *Grid, visited map[*Cell]bool, r *rand.Rand) *Cell {
+	for _, row := range g.grid {
+		for _, cell := range row {
+			if cell == nil || visited[cell] {
+				continue
+			}
+			neighbors := []*Cell{}
+			for _, n := range cell.Neighbors() {
+				if visited[n] {
+					neighbors = append(neighbors, n)
+				}
+			}
+			if len(neighbors) > 0 {
+				cell.Link(neighbors[r.Intn(len(neighbors))])
+				visited[cell] = true
+				return cell
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package maze
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestHuntAndKill(t *testing.T) {
+	sizes := []struct {
+		rows, columns int64
+	}{
+		{1, 1},
+		{1, 8},
+		{8, 1},
+		{10, 10},
+		{7, 13},
+	}
+	for _, s := range sizes {
+		for seed := int64(0); seed < 5; seed++ {
+			g := NewGrid(s.rows, s.columns)
+			HuntAndKillRand(&g, rand.New(rand.NewSource(seed)))
+			if !IsPerfect(&g) {
+				t.Errorf("%dx%d seed %d: maze is not perfect:\n%s", s.rows, s.columns, seed, g.ToString())
+			}
+		}
+	}
+}
+
+func TestHuntAndKillTerminates(t *testing.T) {
+	grids := []struct {
+		name string
+		grid func() *Grid
+	}{
+		{"Open", func() *Grid { g := NewGrid(10, 10); return &g }},
+		// The hunt can never reach the second region, so it must give up
+		// rather than searching forever
+		{"SplitByMask", func() *Grid { return NewMaskedGrid(maskFromString(t, "....X.....\n....X.....\n....X.....")) }},
+	}
+	for _, tc := range grids {
+		t.Run(tc.name, func(t *testing.T) {
+			g := tc.grid()
+			done := make(chan bool)
+			go func() {
+				HuntAndKillRand(g, rand.New(rand.NewSource(1)))
+				close(done)
+			}()
+			select {
+			case <-done:
+			case <-time.After(10 * time.Second):
+				t.Fatal("HuntAndKill did not finish")
+			}
+			if len(Cycles(g)) != 0 {
+				t.Errorf("maze has loops:\n%s", g.ToString())
+			}
+		})
+	}
+}
@@ -0,0 +1,316 @@
+package maze
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// gridJSON is the serialized form of a grid
+type gridJSON struct {
+	// Rows and Columns indicate the size of the grid
+	Rows    int64 `json:"rows"`
+	Columns int64 `json:"columns"`
+	// The directions each cell is linked in, indexed by row and then column
+	Links [][][]string `json:"links"`
+	// The mask of a masked grid, one line per row in the format read by
+	// MaskFromText
+	Mask []string `json:"mask,omitempty"`
+	// Whether the east and west edges are joined, and whether the north and
+	// south edges are joined as well
+	Cylinder bool `json:"cylinder,omitempty"`
+	Torus    bool `json:"torus,omitempty"`
+	// The sides of cells along the border which have been opened
+	Openings []openingJSON `json:"openings,omitempty"`
+}
+
+// openingJSON is the serialized form of an opening in the border of a grid
+type openingJSON struct {
+	Row       int64  `json:"row"`
+	Column    int64  `json:"column"`
+	Direction string `json:"direction"`
+}
+
+// directionLetters are the letters used for each direction in JSON
+var directionLetters = map[Direction]string{North: "N", South: "S", East: "E", West: "W"}
+
+// regionJSON is the serialized form of a rectangular region of a grid
+type regionJSON struct {
+	// Row and Column locate the upper-left cell of the region
+	Row    int64 `json:"row"`
+	Column int64 `json:"column"`
+	gridJSON
+}
+
+// directionNeighbors pairs the letter used for each direction with the
+// neighbor of a cell in that direction
+func directionNeighbors(c *Cell) []struct {
+	name     string
+	neighbor *Cell
+} {
+	return []struct {
+		name     string
+		neighbor *Cell
+	}{{"N", c.North}, {"S", c.South}, {"E", c.East}, {"W", c.West}}
+}
+
+// linkDirections returns the directions a cell is linked in, using the letters
+// N, S, E, and W.  If include is provided, only links to cells it accepts are
+// reported.  A nil cell has no links
+func linkDirections(c *Cell, include func(*Cell) bool) []string {
+	dirs := []string{}
+	if c == nil {
+		return dirs
+	}
+	for _, d := range directionNeighbors(c) {
+		if c.Linked(d.neighbor) && (include == nil || include(d.neighbor)) {
+			dirs = append(dirs, d.name)
+		}
+	}
+	return dirs
+}
+
+// neighborInDirection returns the neighbor of a cell in the direction named by
+// one of the letters N, S, E, or W
+func neighborInDirection(c *Cell, dir string) (*Cell, error) {
+	for _, d := range directionNeighbors(c) {
+		if d.name != dir {
+			continue
+		}
+		if d.neighbor == nil {
+			return nil, fmt.Errorf("cell [%d, %d] is linked %s to a cell outside the grid", c.Row, c.Column, dir)
+		}
+		return d.neighbor, nil
+	}
+	return nil, fmt.Errorf("cell [%d, %d] has unknown direction %q", c.Row, c.Column, dir)
+}
+
+// MarshalJSON records the size and shape of the grid, its mask and border
+// openings, and the directions in which each cell is linked
+func (g *Grid) MarshalJSON() ([]byte, error) {
+	return json.Marshal(g.toJSON(nil))
+}
+
+// toJSON returns the serialized form of the grid.  If include is provided, only
+// links to cells it accepts are recorded
+func (g *Grid) toJSON(include func(*Cell) bool) gridJSON {
+	out := gridJSON{
+		Rows:     g.Rows,
+		Columns:  g.Columns,
+		Links:    make([][][]string, g.Rows),
+		Cylinder: g.cylinder,
+		Torus:    g.torus}
+	for r, row := range g.grid {
+		out.Links[r] = make([][]string, len(row))
+		for c, cell := range row {
+			out.Links[r][c] = linkDirections(cell, include)
+		}
+	}
+	if g.mask != nil {
+		for _, bits := range g.mask.bits {
+			var line strings.Builder
+			for _, enabled := range bits {
+				if enabled {
+					line.WriteByte('.')
+				} else {
+					line.WriteByte('X')
+				}
+			}
+			out.Mask = append(out.Mask, line.String())
+		}
+	}
+	for o := range g.openings {
+		out.Openings = append(out.Openings, openingJSON{o.cell.Row, o.cell.Column, directionLetters[o.dir]})
+	}
+	sort.Slice(out.Openings, func(i, j int) bool {
+		a, b := out.Openings[i], out.Openings[j]
+		if a.Row != b.Row {
+			return a.Row < b.Row
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		return a.Direction < b.Direction
+	})
+	return out
+}
+
+// LoadGridJSON reconstructs a grid from the JSON produced by MarshalJSON,
+// including its mask, shape, and border openings
+func LoadGridJSON(r io.Reader) (*Grid, error) {
+	var in gridJSON
+	if err := json.NewDecoder(r).Decode(&in); err != nil {
+		return nil, fmt.Errorf("decoding grid: %v", err)
+	}
+	return in.newGrid()
+}
+
+// newGrid builds the grid recorded in the serialized form
+func (in *gridJSON) newGrid() (*Grid, error) {
+	if in.Rows < 0 || in.Columns < 0 {
+		return nil, fmt.Errorf("grid dimensions invalid: [%d, %d]", in.Rows, in.Columns)
+	}
+	if int64(len(in.Links)) != in.Rows {
+		return nil, fmt.Errorf("grid has %d rows but links are given for %d", in.Rows, len(in.Links))
+	}
+	// Check every row before allocating the cells, so that a bogus size cannot
+	// exhaust memory
+	for r, row := range in.Links {
+		if int64(len(row)) != in.Columns {
+			return nil, fmt.Errorf("grid has %d columns but row %d has links for %d", in.Columns, r, len(row))
+		}
+	}
+	if in.Cylinder || in.Torus {
+		if err := checkWrap(in.Rows, in.Columns, in.Torus); err != nil {
+			return nil, err
+		}
+	}
+
+	g := &Grid{
+		Rows:     in.Rows,
+		Columns:  in.Columns,
+		grid:     make([][]*Cell, in.Rows),
+		cylinder: in.Cylinder || in.Torus,
+		torus:    in.Torus}
+	if in.Mask != nil {
+		mask, err := MaskFromText(strings.NewReader(strings.Join(in.Mask, "\n")))
+		if err != nil {
+			return nil, err
+		}
+		if mask.Rows != in.Rows || mask.Columns != in.Columns {
+			return nil, fmt.Errorf("grid is [%d, %d] but its mask is [%d, %d]", in.Rows, in.Columns, mask.Rows, mask.Columns)
+		}
+		g.mask = mask
+	}
+	g.prepareGrid()
+	g.configureCells()
+
+	for r, row := range in.Links {
+		for c, dirs := range row {
+			cell := g.At(int64(r), int64(c))
+			if cell == nil && len(dirs) > 0 {
+				return nil, fmt.Errorf("cell [%d, %d] is disabled but has links", r, c)
+			}
+			for _, dir := range dirs {
+				neighbor, err := neighborInDirection(cell, dir)
+				if err != nil {
+					return nil, err
+				}
+				cell.Link(neighbor)
+			}
+		}
+	}
+	for _, o := range in.Openings {
+		dir, ok := Direction(-1), false
+		for d, letter := range directionLetters {
+			if letter == o.Direction {
+				dir, ok = d, true
+			}
+		}
+		if !ok {
+			return nil, fmt.Errorf("opening at [%d, %d] has unknown direction %q", o.Row, o.Column, o.Direction)
+		}
+		if err := g.OpenBorder(g.At(o.Row, o.Column), dir); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+// inRegion returns a function which reports whether a cell lies within a
+// rectangular region of the grid
+func inRegion(row0, col0, rows, cols int64) func(*Cell) bool {
+	return func(c *Cell) bool {
+		return c != nil && c.Row >= row0 && c.Row < row0+rows && c.Column >= col0 && c.Column < col0+cols
+	}
+}
+
+// checkRegion returns an error if a rectangular region doesn't fit in the grid
+func (g *Grid) checkRegion(row0, col0, rows, cols int64) error {
+	if row0 < 0 || col0 < 0 || rows < 0 || cols < 0 || row0+rows > g.Rows || col0+cols > g.Columns {
+		return fmt.Errorf("region [%d, %d] of size [%d, %d] does not fit in a [%d, %d] grid",
+			row0, col0, rows, cols, g.Rows, g.Columns)
+	}
+	return nil
+}
+
+// MarshalRegion records the links within a rectangular region of the grid which
+// has its upper-left cell at [row0, col0].  Only links between two cells in the
+// region are recorded
+func (g *Grid) MarshalRegion(row0, col0, rows, cols int64) ([]byte, error) {
+	if err := g.checkRegion(row0, col0, rows, cols); err != nil {
+		return nil, err
+	}
+	include := inRegion(row0, col0, rows, cols)
+	out := regionJSON{
+		Row:    row0,
+		Column: col0,
+		gridJSON: gridJSON{
+			Rows:    rows,
+			Columns: cols,
+			Links:   make([][][]string, rows)}}
+	for r := int64(0); r < rows; r++ {
+		out.Links[r] = make([][]string, cols)
+		for c := int64(0); c < cols; c++ {
+			out.Links[r][c] = linkDirections(g.At(row0+r, col0+c), include)
+		}
+	}
+	return json.Marshal(out)
+}
+
+// ApplyRegion replaces the links within a rectangular region of the grid with
+// those recorded by MarshalRegion.  Two cells in the region are linked if
+// either of them records the link.  Links which leave the region are not
+// changed.  If the data is malformed, the grid is left untouched
+func (g *Grid) ApplyRegion(data []byte) error {
+	var in regionJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return fmt.Errorf("decoding region: %v", err)
+	}
+	if err := g.checkRegion(in.Row, in.Column, in.Rows, in.Columns); err != nil {
+		return err
+	}
+	if int64(len(in.Links)) != in.Rows {
+		return fmt.Errorf("region has %d rows but links are given for %d", in.Rows, len(in.Links))
+	}
+
+	// Collect every link before changing anything
+	include := inRegion(in.Row, in.Column, in.Rows, in.Columns)
+	links := map[[2]*Cell]bool{}
+	for r, row := range in.Links {
+		if int64(len(row)) != in.Columns {
+			return fmt.Errorf("region has %d columns but row %d has links for %d", in.Columns, r, len(row))
+		}
+		for c, dirs := range row {
+			cell := g.At(in.Row+int64(r), in.Column+int64(c))
+			if cell == nil && len(dirs) > 0 {
+				return fmt.Errorf("cell [%d, %d] is disabled but has links", in.Row+int64(r), in.Column+int64(c))
+			}
+			for _, dir := range dirs {
+				neighbor, err := neighborInDirection(cell, dir)
+				if err != nil {
+					return err
+				}
+				if !include(neighbor) {
+					return fmt.Errorf("cell [%d, %d] is linked %s to a cell outside the region", cell.Row, cell.Column, dir)
+				}
+				links[[2]*Cell{cell, neighbor}] = true
+				links[[2]*Cell{neighbor, cell}] = true
+			}
+		}
+	}
+
+	ForEachAdjacentPair(g, func(a, b *Cell) {
+		if !include(a) || !include(b) {
+			return
+		}
+		if links[[2]*Cell{a, b}] {
+			a.Link(b)
+		} else {
+			a.Unlink(b)
+		}
+	})
+	return nil
+}
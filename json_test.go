@@ -0,0 +1,201 @@
+package maze
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestGridJSONRoundTrip(t *testing.T) {
+	opened := func() *Grid {
+		g := NewGrid(4, 5)
+		g.OpenBorder(g.At(0, 0), West)
+		g.OpenBorder(g.At(3, 4), South)
+		return &g
+	}
+	grids := []struct {
+		name string
+		grid func() *Grid
+	}{
+		{"Rectangle", func() *Grid { g := NewGrid(4, 5); return &g }},
+		{"Empty", func() *Grid { g := NewGrid(0, 0); return &g }},
+		{"Masked", func() *Grid { return NewMaskedGrid(maskFromString(t, ".....\n.XX..\n....X\n.....")) }},
+		{"Cylinder", func() *Grid { g := NewCylinderGrid(4, 5); return &g }},
+		{"Torus", func() *Grid { g := NewTorusGrid(4, 5); return &g }},
+		{"Openings", opened},
+	}
+	for _, tc := range grids {
+		t.Run(tc.name, func(t *testing.T) {
+			g := tc.grid()
+			if g.Size() > 0 {
+				RecursiveBacktrackerRand(g, rand.New(rand.NewSource(7)))
+			}
+			data, err := json.Marshal(g)
+			if err != nil {
+				t.Fatal(err)
+			}
+			loaded, err := LoadGridJSON(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("LoadGridJSON: %v\n%s", err, data)
+			}
+			if !Equal(g, loaded) {
+				t.Errorf("loaded grid differs:\n%s\nwant:\n%s", loaded.ToString(), g.ToString())
+			}
+			again, err := json.Marshal(loaded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(data, again) {
+				t.Errorf("JSON changed after a round trip:\n%s\nwant:\n%s", again, data)
+			}
+			if loaded.ToString() != g.ToString() {
+				t.Errorf("ToString changed after a round trip:\n%s\nwant:\n%s", loaded.ToString(), g.ToString())
+			}
+		})
+	}
+}
+
+func TestLoadGridJSONErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+	}{
+		{"Malformed", `{"rows":`},
+		{"NegativeSize", `{"rows":-1,"columns":2,"links":[]}`},
+		{"MissingRow", `{"rows":2,"columns":1,"links":[[[]]]}`},
+		{"ShortRow", `{"rows":2,"columns":2,"links":[[[],[]],[[]]]}`},
+		{"HugeColumns", `{"rows":1,"columns":4000000000000,"links":[[]]}`},
+		{"NarrowCylinder", `{"rows":1,"columns":2,"links":[[[],[]]],"cylinder":true}`},
+		{"ShortTorus", `{"rows":2,"columns":3,"links":[[[],[],[]],[[],[],[]]],"torus":true}`},
+		{"LinkOffGrid", `{"rows":1,"columns":2,"links":[[["W"],[]]]}`},
+		{"UnknownDirection", `{"rows":1,"columns":2,"links":[[["Q"],[]]]}`},
+		{"MaskSize", `{"rows":1,"columns":2,"links":[[[],[]]],"mask":["..."]}`},
+		{"DisabledCellLinked", `{"rows":1,"columns":2,"links":[[["E"],[]]],"mask":["X."]}`},
+		{"OpeningInside", `{"rows":1,"columns":2,"links":[[[],[]]],"openings":[{"row":0,"column":0,"direction":"E"}]}`},
+		{"OpeningDirection", `{"rows":1,"columns":2,"links":[[[],[]]],"openings":[{"row":0,"column":0,"direction":"Q"}]}`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := LoadGridJSON(strings.NewReader(tc.json)); err == nil {
+				t.Errorf("LoadGridJSON(%s) returned no error", tc.json)
+			}
+		})
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	g := linkedGrid(2, 3, [][4]int64{{0, 0, 0, 1}, {0, 1, 1, 1}, {1, 1, 1, 2}, {1, 2, 0, 2}})
+	g.OpenBorder(g.At(1, 0), West)
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"rows":2,"columns":3,"links":[[["E"],["S","W"],["S"]],[[],["N","E"],["N","W"]]],` +
+		`"openings":[{"row":1,"column":0,"direction":"W"}]}`
+	if string(data) != want {
+		t.Errorf("MarshalJSON() = %s, want %s", data, want)
+	}
+}
+
+func TestMarshalJSONLinks(t *testing.T) {
+	g := NewGrid(5, 6)
+	BinaryTreeRand(&g, rand.New(rand.NewSource(1)))
+	data, err := json.Marshal(&g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Rows    int64        `json:"rows"`
+		Columns int64        `json:"columns"`
+		Links   [][][]string `json:"links"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Rows != 5 || decoded.Columns != 6 || len(decoded.Links) != 5 {
+		t.Fatalf("MarshalJSON() recorded a [%d, %d] grid with %d rows of links", decoded.Rows, decoded.Columns, len(decoded.Links))
+	}
+	for _, cell := range g.Cells() {
+		got := strings.Join(decoded.Links[cell.Row][cell.Column], "")
+		want := ""
+		for _, d := range []struct {
+			letter   string
+			neighbor *Cell
+		}{{"N", cell.North}, {"S", cell.South}, {"E", cell.East}, {"W", cell.West}} {
+			if cell.Linked(d.neighbor) {
+				want += d.letter
+			}
+		}
+		if got != want {
+			t.Errorf("cell [%d, %d] is linked %q, want %q", cell.Row, cell.Column, got, want)
+		}
+	}
+}
+
+func TestMarshalRegion(t *testing.T) {
+	// The passages [0, 2]-[1, 2] and [1, 0]-[2, 0] lead out of the region, so
+	// they aren't recorded
+	g := serpentine(3, 3)
+	data, err := g.MarshalRegion(1, 1, 2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"row":1,"column":1,"rows":2,"columns":2,"links":[[["E"],["W"]],[["E"],["W"]]]}`
+	if string(data) != want {
+		t.Errorf("MarshalRegion() = %s, want %s", data, want)
+	}
+}
+
+func TestApplyRegion(t *testing.T) {
+	from := NewGrid(6, 6)
+	RecursiveBacktrackerRand(&from, rand.New(rand.NewSource(1)))
+	data, err := from.MarshalRegion(1, 2, 3, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := NewGrid(6, 6)
+	RecursiveBacktrackerRand(&to, rand.New(rand.NewSource(2)))
+	before := to.Clone()
+	if err := to.ApplyRegion(data); err != nil {
+		t.Fatal(err)
+	}
+
+	inside := func(c *Cell) bool { return c.Row >= 1 && c.Row < 4 && c.Column >= 2 && c.Column < 6 }
+	ForEachAdjacentPair(&to, func(a, b *Cell) {
+		want := before.At(a.Row, a.Column).Linked(before.At(b.Row, b.Column))
+		if inside(a) && inside(b) {
+			want = from.At(a.Row, a.Column).Linked(from.At(b.Row, b.Column))
+		}
+		if a.Linked(b) != want {
+			t.Errorf("passage [%d, %d]-[%d, %d] is %v, want %v", a.Row, a.Column, b.Row, b.Column, a.Linked(b), want)
+		}
+	})
+}
+
+func TestApplyRegionErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+	}{
+		{"Malformed", `{"row":`},
+		{"OutsideGrid", `{"row":2,"column":0,"rows":2,"columns":1,"links":[[[]],[[]]]}`},
+		{"NegativeSize", `{"row":0,"column":0,"rows":-1,"columns":1,"links":[]}`},
+		{"MissingRow", `{"row":0,"column":0,"rows":2,"columns":1,"links":[[[]]]}`},
+		{"ShortRow", `{"row":0,"column":0,"rows":1,"columns":2,"links":[[[]]]}`},
+		{"UnknownDirection", `{"row":0,"column":0,"rows":1,"columns":2,"links":[[["Q"],[]]]}`},
+		{"LeavesRegion", `{"row":0,"column":0,"rows":1,"columns":2,"links":[[[],["E"]]]}`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := serpentine(3, 3)
+			if err := g.ApplyRegion([]byte(tc.json)); err == nil {
+				t.Errorf("ApplyRegion(%s) returned no error", tc.json)
+			}
+			if !Equal(g, serpentine(3, 3)) {
+				t.Errorf("ApplyRegion(%s) changed the grid:\n%s", tc.json, g.ToString())
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package maze
+
+import (
+	"math/rand"
+)
+
+// RandomizedKruskal uses a randomized version of Kruskal's algorithm to create a
+// maze in a rectangular grid.  Every pair of neighboring cells is considered in
+// a random order, and the pair is linked whenever its cells aren't already
+// connected.  The result has many short dead ends
+func RandomizedKruskal(g *Grid) {
+	RandomizedKruskalRand(g, defaultRand)
+}
+
+// RandomizedKruskalRand is RandomizedKruskal using the provided random source
+func RandomizedKruskalRand(g *Grid, r *rand.Rand) {
+	sets := newUnionFind(g.Cells())
+
+	pairs := [][2]*Cell{}
+	ForEachAdjacentPair(g, func(a, b *Cell) {
+		pairs = append(pairs, [2]*Cell{a, b})
+	})
+	r.Shuffle(len(pairs), func(i, j int) {
+		pairs[i], pairs[j] = pairs[j], pairs[i]
+	})
+	for _, pair := range pairs {
+		if sets.union(pair[0], pair[1]) {
+			pair[0].Link(pair[1])
+		}
+	}
+}
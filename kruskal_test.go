@@ -0,0 +1,71 @@
+package maze
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestRandomizedKruskal(t *testing.T) {
+	sizes := []struct {
+		rows, columns int64
+	}{
+		{1, 1},
+		{1, 10},
+		{10, 1},
+		{9, 11},
+	}
+	for _, s := range sizes {
+		for seed := int64(0); seed < 5; seed++ {
+			g := NewGrid(s.rows, s.columns)
+			RandomizedKruskalRand(&g, rand.New(rand.NewSource(seed)))
+			// Merging the two ends of every passage leaves a single set, and
+			// never finds a passage between cells already in the same set
+			sets := newUnionFind(g.Cells())
+			passages := int64(0)
+			ForEachAdjacentPair(&g, func(a, b *Cell) {
+				if a.Linked(b) {
+					passages++
+					if !sets.union(a, b) {
+						t.Errorf("%dx%d seed %d: passage [%d, %d]-[%d, %d] closes a loop", s.rows, s.columns, seed, a.Row, a.Column, b.Row, b.Column)
+					}
+				}
+			})
+			if passages != g.Size()-1 {
+				t.Errorf("%dx%d seed %d: carved %d passages, want %d", s.rows, s.columns, seed, passages, g.Size()-1)
+			}
+			if sets.count != 1 {
+				t.Errorf("%dx%d seed %d: maze is in %d pieces:\n%s", s.rows, s.columns, seed, sets.count, g.ToString())
+			}
+		}
+	}
+}
+
+func TestUnionFind(t *testing.T) {
+	g := NewGrid(1, 4)
+	cells := g.Cells()
+	sets := newUnionFind(cells)
+	if sets.count != 4 {
+		t.Fatalf("count = %d, want 4", sets.count)
+	}
+	steps := []struct {
+		a, b  int
+		want  bool
+		count int
+	}{
+		{0, 1, true, 3},
+		{1, 0, false, 3},
+		{2, 3, true, 2},
+		{1, 3, true, 1},
+		{0, 2, false, 1},
+	}
+	for _, s := range steps {
+		if got := sets.union(cells[s.a], cells[s.b]); got != s.want || sets.count != s.count {
+			t.Errorf("union(%d, %d) = %v with %d sets, want %v with %d", s.a, s.b, got, sets.count, s.want, s.count)
+		}
+	}
+	for _, c := range cells {
+		if sets.find(c) != sets.find(cells[0]) {
+			t.Errorf("cell %d is not in the same set as cell 0", c.Column)
+		}
+	}
+}
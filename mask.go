@@ -0,0 +1,223 @@
+package maze
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"image/png"
+	"io"
+	"math/rand"
+	"strings"
+)
+
+// maskBrightness is the lowest gray level of a pixel which enables its cell
+// when a mask is read from an image
+const maskBrightness = 128
+
+// Mask records which cells of a rectangular grid are enabled.  A masked grid
+// only contains its enabled cells, which allows mazes to be carved in arbitrary
+// shapes
+type Mask struct {
+	// Rows and Columns indicate the size of the mask
+	Rows, Columns int64
+	// Whether each cell is enabled, indexed by row and then column
+	bits [][]bool
+	// The number of enabled cells
+	count int64
+}
+
+// NewMask creates a mask of the given size with every cell enabled.  An error is
+// returned if the dimensions are negative
+func NewMask(rows, columns int64) (*Mask, error) {
+	if rows < 0 || columns < 0 {
+		return nil, fmt.Errorf("mask dimensions invalid: [%d, %d]", rows, columns)
+	}
+	m := &Mask{
+		Rows:    rows,
+		Columns: columns,
+		bits:    make([][]bool, rows),
+		count:   rows * columns}
+	for r := range m.bits {
+		m.bits[r] = make([]bool, columns)
+		for c := range m.bits[r] {
+			m.bits[r][c] = true
+		}
+	}
+	return m, nil
+}
+
+// MaskFromPNG reads a mask from a PNG image, with one cell for each pixel.
+// Light pixels enable their cells and dark or transparent pixels disable them
+func MaskFromPNG(r io.Reader) (*Mask, error) {
+	img, err := png.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("decoding mask image: %v", err)
+	}
+	bounds := img.Bounds()
+	m, err := NewMask(int64(bounds.Dy()), int64(bounds.Dx()))
+	if err != nil {
+		return nil, err
+	}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			gray := color.GrayModel.Convert(img.At(x, y)).(color.Gray)
+			if gray.Y < maskBrightness {
+				m.set(int64(y-bounds.Min.Y), int64(x-bounds.Min.X), false)
+			}
+		}
+	}
+	return m, nil
+}
+
+// MaskFromText reads a mask from text in which each line is a row.  An X or #
+// disables a cell and a period or space enables it.  The mask is as wide as the
+// longest line, and the cells missing from the ends of shorter lines are
+// disabled
+func MaskFromText(r io.Reader) (*Mask, error) {
+	lines := [][]rune{}
+	columns := 0
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := []rune(strings.TrimRight(scanner.Text(), "\r"))
+		lines = append(lines, line)
+		if len(line) > columns {
+			columns = len(line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading mask: %v", err)
+	}
+	if len(lines) == 0 || columns == 0 {
+		return nil, fmt.Errorf("mask text is empty")
+	}
+
+	m, err := NewMask(int64(len(lines)), int64(columns))
+	if err != nil {
+		return nil, err
+	}
+	for row, line := range lines {
+		for col := 0; col < columns; col++ {
+			if col >= len(line) {
+				m.set(int64(row), int64(col), false)
+				continue
+			}
+			switch line[col] {
+			case 'X', '#':
+				m.set(int64(row), int64(col), false)
+			case '.', ' ':
+			default:
+				return nil, fmt.Errorf("unexpected character %q in mask at [%d, %d]", line[col], row, col)
+			}
+		}
+	}
+	return m, nil
+}
+
+// Enabled returns true if the cell at the given position is enabled.  Positions
+// outside the mask are never enabled
+func (m *Mask) Enabled(row, column int64) bool {
+	if row < 0 || column < 0 || row >= m.Rows || column >= m.Columns {
+		return false
+	}
+	return m.bits[row][column]
+}
+
+// Set enables or disables the cell at the given position.  An error is returned
+// if the position is outside the mask
+func (m *Mask) Set(row, column int64, enabled bool) error {
+	if row < 0 || column < 0 || row >= m.Rows || column >= m.Columns {
+		return fmt.Errorf("mask position invalid: [%d, %d]", row, column)
+	}
+	m.set(row, column, enabled)
+	return nil
+}
+
+// set enables or disables the cell at a position known to be inside the mask
+func (m *Mask) set(row, column int64, enabled bool) {
+	if m.bits[row][column] != enabled {
+		m.bits[row][column] = enabled
+		if enabled {
+			m.count++
+		} else {
+			m.count--
+		}
+	}
+}
+
+// Count returns the number of enabled cells
+func (m *Mask) Count() int64 {
+	return m.count
+}
+
+// RandomLocation returns the position of a random enabled cell.  An error is
+// returned if no cells are enabled
+func (m *Mask) RandomLocation() (row, column int64, err error) {
+	if m.count == 0 {
+		return -1, -1, fmt.Errorf("mask has no enabled cells")
+	}
+	row, column = m.randomLocation(defaultRand)
+	return row, column, nil
+}
+
+// randomLocation returns the position of a random enabled cell chosen using the
+// provided random source.  If no cells are enabled, both are -1
+func (m *Mask) randomLocation(r *rand.Rand) (row, column int64) {
+	if m.count == 0 {
+		return -1, -1
+	}
+	n := r.Int63n(m.count)
+	for row, bits := range m.bits {
+		for column, enabled := range bits {
+			if !enabled {
+				continue
+			}
+			if n == 0 {
+				return int64(row), int64(column)
+			}
+			n--
+		}
+	}
+	return -1, -1
+}
+
+// clone returns an independent copy of the mask
+func (m *Mask) clone() *Mask {
+	c := &Mask{
+		Rows:    m.Rows,
+		Columns: m.Columns,
+		bits:    make([][]bool, m.Rows),
+		count:   m.count}
+	for r := range m.bits {
+		c.bits[r] = append([]bool(nil), m.bits[r]...)
+	}
+	return c
+}
+
+// appendRow adds a row of enabled cells to the bottom of the mask
+func (m *Mask) appendRow() {
+	row := make([]bool, m.Columns)
+	for c := range row {
+		row[c] = true
+	}
+	m.bits = append(m.bits, row)
+	m.Rows++
+	m.count += m.Columns
+}
+
+// rowRange returns a new mask containing the rows of this mask from the first
+// row given up to but not including the second
+func (m *Mask) rowRange(from, to int64) *Mask {
+	sub := &Mask{
+		Rows:    to - from,
+		Columns: m.Columns,
+		bits:    make([][]bool, to-from)}
+	for r := range sub.bits {
+		sub.bits[r] = append([]bool(nil), m.bits[from+int64(r)]...)
+		for _, enabled := range sub.bits[r] {
+			if enabled {
+				sub.count++
+			}
+		}
+	}
+	return sub
+}
@@ -0,0 +1,190 @@
+package maze
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// generatorCase is a maze generator exercised by the tests.  Generators marked
+// connected carve every cell of the masked region they start in
+type generatorCase struct {
+	name      string
+	generate  func(*Grid, *rand.Rand)
+	connected bool
+}
+
+// generators are the maze generators which fill a whole grid
+var generators = []generatorCase{
+	{"AldousBroder", AldousBroderRand, true},
+	{"BinaryTree", BinaryTreeRand, false},
+	{"Ellers", EllersRand, false},
+	{"GrowingTree", func(g *Grid, r *rand.Rand) { GrowingTreeRand(g, PickRandomFrom(r), r) }, true},
+	{"HuntAndKill", HuntAndKillRand, true},
+	{"RandomizedKruskal", RandomizedKruskalRand, false},
+	{"RandomizedPrim", RandomizedPrimRand, true},
+	{"RecursiveBacktracker", RecursiveBacktrackerRand, true},
+	{"RecursiveDivision", RecursiveDivisionRand, false},
+	{"Wilsons", WilsonsRand, true},
+}
+
+// maskFromString builds a mask from text in the format read by MaskFromText
+func maskFromString(t *testing.T, text string) *Mask {
+	t.Helper()
+	m, err := MaskFromText(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestMaskedGeneratorsDonut(t *testing.T) {
+	donut := "......\n" +
+		"......\n" +
+		"..XX..\n" +
+		"..XX..\n" +
+		"......\n" +
+		"......"
+	for _, gen := range generators {
+		t.Run(gen.name, func(t *testing.T) {
+			g := NewMaskedGrid(maskFromString(t, donut))
+			gen.generate(g, rand.New(rand.NewSource(1)))
+			for _, cell := range g.Cells() {
+				for _, l := range cell.Links() {
+					if g.At(l.Row, l.Column) != l {
+						t.Fatalf("cell [%d, %d] is linked into the hole", cell.Row, cell.Column)
+					}
+				}
+			}
+			if c := Cycles(g); len(c) != 0 {
+				t.Errorf("maze has %d loops", len(c))
+			}
+			if gen.connected && !IsPerfect(g) {
+				t.Errorf("maze is not perfect:\n%s", g.ToString())
+			}
+		})
+	}
+}
+
+func TestMaskedGeneratorsDisconnected(t *testing.T) {
+	masks := []struct {
+		name string
+		text string
+	}{
+		{"IsolatedCell", "..X."},
+		{"TwoIslands", "..X..\n..X.."},
+	}
+	for _, m := range masks {
+		for _, gen := range generators {
+			t.Run(m.name+"/"+gen.name, func(t *testing.T) {
+				for seed := int64(0); seed < 10; seed++ {
+					g := NewMaskedGrid(maskFromString(t, m.text))
+					gen.generate(g, rand.New(rand.NewSource(seed)))
+					if c := Cycles(g); len(c) != 0 {
+						t.Fatalf("seed %d: maze has %d loops", seed, len(c))
+					}
+					if !gen.connected {
+						continue
+					}
+					// Exactly one region is carved, and all of it is
+					var carved []*Cell
+					for _, cell := range g.Cells() {
+						if cell.hasLinks() {
+							carved = append(carved, cell)
+						}
+					}
+					if len(carved) == 0 {
+						continue
+					}
+					if want := region(carved[0]); len(Reachable(carved[0])) != len(want) || len(carved) != len(want) {
+						t.Fatalf("seed %d: carved %d cells of a %d cell region", seed, len(carved), len(want))
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestNewMaskInvalidDimensions(t *testing.T) {
+	for _, dims := range [][2]int64{{-1, 5}, {5, -1}, {-1, -1}} {
+		if _, err := NewMask(dims[0], dims[1]); err == nil {
+			t.Errorf("NewMask(%d, %d) returned no error", dims[0], dims[1])
+		}
+	}
+}
+
+func TestMaskSet(t *testing.T) {
+	m, err := NewMask(2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Set(1, 2, false); err != nil {
+		t.Fatal(err)
+	}
+	if m.Enabled(1, 2) || m.Count() != 5 {
+		t.Errorf("Set(1, 2, false) left Enabled = %v, Count = %d", m.Enabled(1, 2), m.Count())
+	}
+	for _, pos := range [][2]int64{{-1, 0}, {0, -1}, {2, 0}, {0, 3}} {
+		if err := m.Set(pos[0], pos[1], false); err == nil {
+			t.Errorf("Set(%d, %d) returned no error", pos[0], pos[1])
+		}
+	}
+}
+
+func TestMaskRandomLocation(t *testing.T) {
+	m := maskFromString(t, "X.\nXX")
+	row, column, err := m.RandomLocation()
+	if err != nil || row != 0 || column != 1 {
+		t.Errorf("RandomLocation() = %d, %d, %v, want 0, 1", row, column, err)
+	}
+	m.Set(0, 1, false)
+	if _, _, err := m.RandomLocation(); err == nil {
+		t.Error("RandomLocation() on an empty mask returned no error")
+	}
+	if c := NewMaskedGrid(m).RandomCellRand(rand.New(rand.NewSource(1))); c != nil {
+		t.Errorf("RandomCellRand() on an empty mask = [%d, %d], want nil", c.Row, c.Column)
+	}
+}
+
+func TestMaskFromPNG(t *testing.T) {
+	// Each row holds a pixel which enables its cell and one which disables it
+	pixels := [][2]color.Color{
+		{color.White, color.Black},
+		{color.Gray{Y: 200}, color.Gray{Y: 60}},
+		{color.Gray{Y: maskBrightness}, color.Gray{Y: maskBrightness - 1}},
+		{color.RGBA{R: 255, G: 255, B: 0, A: 255}, color.NRGBA{R: 255, G: 255, B: 255, A: 0}},
+	}
+	// The image is offset from the origin, which must not shift the cells
+	img := image.NewRGBA(image.Rect(3, 5, 5, 9))
+	for y, row := range pixels {
+		img.Set(3, 5+y, row[0])
+		img.Set(4, 5+y, row[1])
+	}
+	var data bytes.Buffer
+	if err := png.Encode(&data, img); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := MaskFromPNG(&data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Rows != 4 || m.Columns != 2 || m.Count() != 4 {
+		t.Fatalf("MaskFromPNG() = [%d, %d] with %d cells enabled, want [4, 2] with 4", m.Rows, m.Columns, m.Count())
+	}
+	for r := int64(0); r < 4; r++ {
+		if !m.Enabled(r, 0) || m.Enabled(r, 1) {
+			t.Errorf("row %d has cells enabled %v, %v, want true, false", r, m.Enabled(r, 0), m.Enabled(r, 1))
+		}
+	}
+}
+
+func TestMaskFromPNGInvalid(t *testing.T) {
+	if _, err := MaskFromPNG(strings.NewReader("not a png")); err == nil {
+		t.Error("MaskFromPNG() of invalid data succeeded, want an error")
+	}
+}
@@ -0,0 +1,26 @@
+package maze
+
+import (
+	"fmt"
+)
+
+// Overlay combines two mazes of the same size into a new maze.  A wall stands in
+// the result only where it stands in both inputs, so the passages of the result
+// are the union of the passages of the inputs.  Cells disabled by the mask of
+// base are disabled in the result
+func Overlay(base, overlay *Grid) (*Grid, error) {
+	if base.Rows != overlay.Rows || base.Columns != overlay.Columns {
+		return nil, fmt.Errorf("grid dimensions differ: [%d, %d] and [%d, %d]",
+			base.Rows, base.Columns, overlay.Rows, overlay.Columns)
+	}
+
+	g := base.rowRange(0, base.Rows)
+	for _, src := range []*Grid{base, overlay} {
+		ForEachAdjacentPair(src, func(a, b *Cell) {
+			if ga, gb := g.At(a.Row, a.Column), g.At(b.Row, b.Column); a.Linked(b) && ga != nil && gb != nil {
+				ga.Link(gb)
+			}
+		})
+	}
+	return g, nil
+}
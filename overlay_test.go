@@ -0,0 +1,56 @@
+package maze
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestOverlay(t *testing.T) {
+	for seed := int64(0); seed < 5; seed++ {
+		a, b := NewGrid(6, 7), NewGrid(6, 7)
+		RecursiveBacktrackerRand(&a, rand.New(rand.NewSource(seed)))
+		WilsonsRand(&b, rand.New(rand.NewSource(seed)))
+		before := a.Clone()
+		g, err := Overlay(&a, &b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g.Rows != 6 || g.Columns != 7 {
+			t.Fatalf("seed %d: Overlay() is [%d, %d], want [6, 7]", seed, g.Rows, g.Columns)
+		}
+		ForEachAdjacentPair(g, func(x, y *Cell) {
+			want := a.At(x.Row, x.Column).Linked(a.At(y.Row, y.Column)) || b.At(x.Row, x.Column).Linked(b.At(y.Row, y.Column))
+			if x.Linked(y) != want || y.Linked(x) != want {
+				t.Errorf("seed %d: [%d, %d] and [%d, %d] linked = %v, want %v", seed, x.Row, x.Column, y.Row, y.Column, x.Linked(y), want)
+			}
+		})
+		if !Equal(&a, before) {
+			t.Errorf("seed %d: Overlay() changed its input", seed)
+		}
+	}
+}
+
+func TestOverlayMasked(t *testing.T) {
+	base := NewMaskedGrid(maskFromString(t, "..\n.X"))
+	base.At(0, 0).Link(base.At(0, 1))
+	overlay := NewGrid(2, 2)
+	overlay.At(0, 0).Link(overlay.At(1, 0))
+	overlay.At(1, 0).Link(overlay.At(1, 1))
+	g, err := Overlay(base, &overlay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.At(1, 1) != nil || g.Size() != 3 {
+		t.Fatalf("Overlay() enabled %d cells, want the 3 enabled in base", g.Size())
+	}
+	if !g.At(0, 0).Linked(g.At(0, 1)) || !g.At(0, 0).Linked(g.At(1, 0)) || len(g.At(1, 0).Links()) != 1 {
+		t.Errorf("Overlay() =\n%s", g.ToString())
+	}
+}
+
+func TestOverlayDimensionsDiffer(t *testing.T) {
+	a, b := NewGrid(3, 4), NewGrid(4, 3)
+	if g, err := Overlay(&a, &b); err == nil || g != nil {
+		t.Errorf("Overlay() of different sizes = %v, %v, want an error", g, err)
+	}
+}
@@ -0,0 +1,136 @@
+package maze
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math/rand"
+)
+
+// Colors used when rendering mazes
+var (
+	background = color.White
+	wallColor  = color.Black
+	parchment  = color.RGBA{R: 238, G: 226, B: 196, A: 255}
+)
+
+// ToPNG renders the maze as a PNG image in which every cell is a
+// cellSize x cellSize block and walls are wallThickness pixels wide.  The image
+// is (Columns * cellSize + wallThickness) pixels wide and
+// (Rows * cellSize + wallThickness) pixels tall
+func (g *Grid) ToPNG(w io.Writer, cellSize, wallThickness int) error {
+	if cellSize < 1 || wallThickness < 1 {
+		return fmt.Errorf("invalid PNG sizes: cell %d, wall %d", cellSize, wallThickness)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0,
+		int(g.Columns)*cellSize+wallThickness,
+		int(g.Rows)*cellSize+wallThickness))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: background}, image.Point{}, draw.Src)
+
+	for row := int64(0); row <= g.Rows; row++ {
+		for col := int64(0); col < g.Columns; col++ {
+			if g.horizontalWall(row, col) {
+				x, y := int(col)*cellSize, int(row)*cellSize
+				fillRect(img, x, y, x+cellSize+wallThickness, y+wallThickness, wallColor)
+			}
+		}
+	}
+	for row := int64(0); row < g.Rows; row++ {
+		for col := int64(0); col <= g.Columns; col++ {
+			if g.verticalWall(row, col) {
+				x, y := int(col)*cellSize, int(row)*cellSize
+				fillRect(img, x, y, x+wallThickness, y+cellSize+wallThickness, wallColor)
+			}
+		}
+	}
+
+	return png.Encode(w, img)
+}
+
+// ToWeatheredPNG renders the maze as a PNG image which looks hand-drawn or aged.
+// Every wall segment receives a slightly different ink color and thickness,
+// chosen using the provided random source.  The image is
+// (Columns * cellSize + maxThickness) pixels wide and
+// (Rows * cellSize + maxThickness) pixels tall, where maxThickness is
+// 1 + cellSize / 8
+func (g *Grid) ToWeatheredPNG(w io.Writer, cellSize int, r *rand.Rand) error {
+	if cellSize < 1 {
+		return errors.New("cell size must be positive")
+	}
+	if r == nil {
+		return errors.New("a random source is required")
+	}
+
+	maxThickness := 1 + cellSize/8
+	img := image.NewRGBA(image.Rect(0, 0,
+		int(g.Columns)*cellSize+maxThickness,
+		int(g.Rows)*cellSize+maxThickness))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: parchment}, image.Point{}, draw.Src)
+
+	// inkSegment draws a wall from (x0, y0) to (x1, y1) with a random shade and
+	// thickness, centered within the space reserved for the thickest wall
+	inkSegment := func(x0, y0, x1, y1 int) {
+		thickness := 1 + r.Intn(maxThickness)
+		offset := (maxThickness - thickness) / 2
+		shade := uint8(30 + r.Intn(80))
+		ink := color.RGBA{R: shade + 10, G: shade, B: shade - shade/4, A: 255}
+		fillRect(img, x0+offset, y0+offset, x1+offset+thickness, y1+offset+thickness, ink)
+	}
+
+	for row := int64(0); row <= g.Rows; row++ {
+		for col := int64(0); col < g.Columns; col++ {
+			if g.horizontalWall(row, col) {
+				x, y := int(col)*cellSize, int(row)*cellSize
+				inkSegment(x, y, x+cellSize, y)
+			}
+		}
+	}
+	for row := int64(0); row < g.Rows; row++ {
+		for col := int64(0); col <= g.Columns; col++ {
+			if g.verticalWall(row, col) {
+				x, y := int(col)*cellSize, int(row)*cellSize
+				inkSegment(x, y, x, y+cellSize)
+			}
+		}
+	}
+
+	return png.Encode(w, img)
+}
+
+// fillRect fills the rectangle [x0, x1) x [y0, y1) of an image with a color
+func fillRect(img *image.RGBA, x0, y0, x1, y1 int, c color.Color) {
+	draw.Draw(img, image.Rect(x0, y0, x1, y1), &image.Uniform{C: c}, image.Point{}, draw.Src)
+}
+
+// drawLine draws a one pixel wide line between two points of an image
+func drawLine(img *image.RGBA, x0, y0, x1, y1 int, c color.Color) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	err := dx + dy
+	for {
+		img.Set(x0, y0, c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package maze
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"math/rand"
+	"testing"
+)
+
+// decodePNG decodes an image written by one of the PNG renderers
+func decodePNG(t *testing.T, data []byte) image.Image {
+	t.Helper()
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return img
+}
+
+// colorCount returns the number of distinct colors in an image
+func colorCount(img image.Image) int {
+	colors := map[[4]uint32]bool{}
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, g, b, a := img.At(x, y).RGBA()
+			colors[[4]uint32{r, g, b, a}] = true
+		}
+	}
+	return len(colors)
+}
+
+func TestToWeatheredPNG(t *testing.T) {
+	render := func(seed int64) []byte {
+		g := NewGrid(3, 4)
+		RecursiveBacktrackerRand(&g, rand.New(rand.NewSource(1)))
+		var out bytes.Buffer
+		if err := g.ToWeatheredPNG(&out, 16, rand.New(rand.NewSource(seed))); err != nil {
+			t.Fatal(err)
+		}
+		return out.Bytes()
+	}
+	first := render(1)
+	img := decodePNG(t, first)
+	// Walls are at most 1 + 16 / 8 pixels thick
+	if size := img.Bounds().Size(); size.X != 4*16+3 || size.Y != 3*16+3 {
+		t.Errorf("image is %v, want 67x51", size)
+	}
+	// The background and at least two shades of ink
+	if n := colorCount(img); n < 3 {
+		t.Errorf("image has %d colors, want at least 3", n)
+	}
+	if !bytes.Equal(first, render(1)) {
+		t.Error("the same seed rendered different images")
+	}
+	if bytes.Equal(first, render(2)) {
+		t.Error("different seeds rendered the same image")
+	}
+}
+
+func TestToWeatheredPNGErrors(t *testing.T) {
+	g := NewGrid(2, 2)
+	var out bytes.Buffer
+	if err := g.ToWeatheredPNG(&out, 0, rand.New(rand.NewSource(1))); err == nil {
+		t.Error("ToWeatheredPNG() with a cell size of 0 returned no error")
+	}
+	if err := g.ToWeatheredPNG(&out, 8, nil); err == nil {
+		t.Error("ToWeatheredPNG() without a random source returned no error")
+	}
+	if out.Len() != 0 {
+		t.Errorf("ToWeatheredPNG() wrote %d bytes after an error", out.Len())
+	}
+}
+
+func TestToPNG(t *testing.T) {
+	// Only the middle column of a 3x3 grid is open from top to bottom
+	g := linkedGrid(3, 3, [][4]int64{{0, 1, 1, 1}, {1, 1, 2, 1}})
+	var out bytes.Buffer
+	if err := g.ToPNG(&out, 10, 2); err != nil {
+		t.Fatal(err)
+	}
+	img := decodePNG(t, out.Bytes())
+	if size := img.Bounds().Size(); size.X != 3*10+2 || size.Y != 3*10+2 {
+		t.Fatalf("image is %v, want 32x32", size)
+	}
+	black := func(x, y int) bool {
+		r, g, b, _ := img.At(x, y).RGBA()
+		return r == 0 && g == 0 && b == 0
+	}
+	pixels := []struct {
+		name string
+		x, y int
+		wall bool
+	}{
+		{"TopLeftCorner", 0, 0, true},
+		{"BottomRightCorner", 31, 31, true},
+		{"CellCenter", 16, 16, false},
+		{"WallBetweenColumns", 10, 5, true},
+		{"PassageDownMiddle", 16, 10, false},
+		{"WallBetweenRows", 5, 10, true},
+	}
+	for _, p := range pixels {
+		if black(p.x, p.y) != p.wall {
+			t.Errorf("%s pixel (%d, %d) is a wall = %v, want %v", p.name, p.x, p.y, black(p.x, p.y), p.wall)
+		}
+	}
+}
+
+func TestToPNGInvalidSizes(t *testing.T) {
+	g := NewGrid(2, 2)
+	for _, sizes := range [][2]int{{0, 1}, {1, 0}, {-1, 2}} {
+		var out bytes.Buffer
+		if err := g.ToPNG(&out, sizes[0], sizes[1]); err == nil || out.Len() != 0 {
+			t.Errorf("ToPNG(%d, %d) = %v and wrote %d bytes, want an error", sizes[0], sizes[1], err, out.Len())
+		}
+	}
+}
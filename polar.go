@@ -0,0 +1,260 @@
+package maze
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"math/rand"
+)
+
+// PolarCell represents a cell in a circular maze
+type PolarCell struct {
+	// The ring containing this cell, counting outward from the center, and the
+	// position of the cell within its ring
+	Row, Column int64
+	// The neighbors of this cell within its ring
+	Clockwise, CounterClockwise *PolarCell
+	// The neighbor of this cell in the next ring toward the center
+	Inward *PolarCell
+	// The neighbors of this cell in the next ring away from the center
+	Outward []*PolarCell
+	// The cells directly linked to this cell
+	links nodeLinks
+}
+
+var _ Node = (*PolarCell)(nil)
+var _ Graph = (*PolarGrid)(nil)
+
+// Link links one cell to another bidirectionally
+func (c *PolarCell) Link(neighbor *PolarCell) {
+	c.links.add(neighbor)
+	neighbor.links.add(c)
+}
+
+// Unlink removes the bidirectional link between two cells
+func (c *PolarCell) Unlink(neighbor *PolarCell) {
+	c.links.remove(neighbor)
+	neighbor.links.remove(c)
+}
+
+// Linked returns true if a cell is linked to another
+func (c *PolarCell) Linked(neighbor *PolarCell) bool {
+	return neighbor != nil && c.links.has(neighbor)
+}
+
+// LinkNode links this cell to another bidirectionally.  The other cell must be a
+// *PolarCell
+func (c *PolarCell) LinkNode(neighbor Node) {
+	c.Link(neighbor.(*PolarCell))
+}
+
+// LinkedNodes returns the cells this cell is linked to, in the order the links
+// were made
+func (c *PolarCell) LinkedNodes() []Node {
+	return c.links.nodes()
+}
+
+// NeighborNodes returns the direct neighbors of this cell, in the same order as
+// Neighbors
+func (c *PolarCell) NeighborNodes() []Node {
+	ret := []Node{}
+	for _, n := range c.Neighbors() {
+		ret = append(ret, n)
+	}
+	return ret
+}
+
+// Neighbors returns the list of direct neighbors of this cell
+func (c *PolarCell) Neighbors() []*PolarCell {
+	ret := []*PolarCell{}
+	for _, n := range []*PolarCell{c.Clockwise, c.CounterClockwise, c.Inward} {
+		if n != nil {
+			ret = append(ret, n)
+		}
+	}
+	return append(ret, c.Outward...)
+}
+
+// PolarGrid represents a circular maze made of concentric rings.  The center is
+// a single cell, and each ring outside it is divided into enough cells to keep
+// them roughly as wide as they are tall
+type PolarGrid struct {
+	// Rows indicates the number of rings, including the center
+	Rows int64
+	// The cells in each ring
+	grid [][]*PolarCell
+}
+
+// NewPolarGrid creates a new circular grid with the given number of rings.  An
+// error is returned if the number of rings is negative
+func NewPolarGrid(rings int64) (*PolarGrid, error) {
+	if rings < 0 {
+		return nil, fmt.Errorf("polar grid dimensions invalid: %d", rings)
+	}
+	g := &PolarGrid{
+		Rows: rings,
+		grid: make([][]*PolarCell, rings)}
+	g.prepareGrid()
+	g.configureCells()
+	return g, nil
+}
+
+// prepareGrid creates the cells in each ring.  The number of cells in a ring is
+// always a multiple of the number in the ring inside it
+func (g *PolarGrid) prepareGrid() {
+	rowHeight := 1 / float64(g.Rows)
+	for r := int64(0); r < g.Rows; r++ {
+		count := int64(1)
+		if r > 0 {
+			previous := int64(len(g.grid[r-1]))
+			circumference := 2 * math.Pi * float64(r) / float64(g.Rows)
+			cellWidth := circumference / float64(previous)
+			count = previous * int64(math.Round(cellWidth/rowHeight))
+		}
+		g.grid[r] = make([]*PolarCell, count)
+		for c := int64(0); c < count; c++ {
+			g.grid[r][c] = &PolarCell{Row: r, Column: c}
+		}
+	}
+}
+
+// configureCells establishes the neighbors of each cell
+func (g *PolarGrid) configureCells() {
+	for _, cell := range g.Cells() {
+		if cell.Row == 0 {
+			continue
+		}
+		cell.Clockwise = g.At(cell.Row, cell.Column+1)
+		cell.CounterClockwise = g.At(cell.Row, cell.Column-1)
+		ratio := int64(len(g.grid[cell.Row])) / int64(len(g.grid[cell.Row-1]))
+		parent := g.grid[cell.Row-1][cell.Column/ratio]
+		parent.Outward = append(parent.Outward, cell)
+		cell.Inward = parent
+	}
+}
+
+// At accesses a cell from the grid.  Columns wrap around their ring
+func (g *PolarGrid) At(row, column int64) *PolarCell {
+	if row < 0 || row >= g.Rows {
+		return nil
+	}
+	count := int64(len(g.grid[row]))
+	return g.grid[row][((column%count)+count)%count]
+}
+
+// AllCells iterates over all of the cells in the grid, from the center outward
+func (g *PolarGrid) AllCells() <-chan *PolarCell {
+	c := make(chan *PolarCell)
+	go func() {
+		for _, cell := range g.Cells() {
+			c <- cell
+		}
+		close(c)
+	}()
+	return c
+}
+
+// Cells returns all of the cells in the grid, from the center outward
+func (g *PolarGrid) Cells() []*PolarCell {
+	cells := make([]*PolarCell, 0, g.Size())
+	for _, row := range g.grid {
+		cells = append(cells, row...)
+	}
+	return cells
+}
+
+// Nodes returns all of the cells in the grid, from the center outward
+func (g *PolarGrid) Nodes() []Node {
+	ret := []Node{}
+	for _, cell := range g.Cells() {
+		ret = append(ret, cell)
+	}
+	return ret
+}
+
+// RandomCell returns a random cell from the grid
+func (g *PolarGrid) RandomCell() *PolarCell {
+	return g.RandomCellRand(defaultRand)
+}
+
+// RandomCellRand returns a random cell from the grid chosen using the provided
+// random source.  Every cell is equally likely, however small its ring.  If
+// the grid has no cells, it returns nil
+func (g *PolarGrid) RandomCellRand(r *rand.Rand) *PolarCell {
+	size := g.Size()
+	if size == 0 {
+		return nil
+	}
+	return g.Cells()[r.Int63n(size)]
+}
+
+// RandomNode returns a random cell from the grid chosen using the provided
+// random source, or nil if the grid has no cells
+func (g *PolarGrid) RandomNode(r *rand.Rand) Node {
+	if c := g.RandomCellRand(r); c != nil {
+		return c
+	}
+	return nil
+}
+
+// Size returns the number of cells in the grid
+func (g *PolarGrid) Size() int64 {
+	size := int64(0)
+	for _, row := range g.grid {
+		size += int64(len(row))
+	}
+	return size
+}
+
+// ToPNG renders the maze as a PNG image in which every ring is cellSize pixels
+// thick.  The image is (2 * Rows * cellSize + 1) pixels square
+func (g *PolarGrid) ToPNG(w io.Writer, cellSize int) error {
+	if cellSize < 1 {
+		return fmt.Errorf("invalid PNG cell size: %d", cellSize)
+	}
+
+	size := 2*int(g.Rows)*cellSize + 1
+	center := float64(size / 2)
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: background}, image.Point{}, draw.Src)
+
+	point := func(radius, theta float64) (int, int) {
+		return int(math.Round(center + radius*math.Cos(theta))), int(math.Round(center + radius*math.Sin(theta)))
+	}
+	// arc draws part of a circle as a series of short lines
+	arc := func(radius, from, to float64) {
+		steps := int(math.Ceil(radius*(to-from)/2)) + 1
+		x0, y0 := point(radius, from)
+		for i := 1; i <= steps; i++ {
+			x1, y1 := point(radius, from+(to-from)*float64(i)/float64(steps))
+			drawLine(img, x0, y0, x1, y1, wallColor)
+			x0, y0 = x1, y1
+		}
+	}
+
+	for _, cell := range g.Cells() {
+		if cell.Row == 0 {
+			continue
+		}
+		theta := 2 * math.Pi / float64(len(g.grid[cell.Row]))
+		inner := float64(cell.Row * int64(cellSize))
+		outer := float64((cell.Row + 1) * int64(cellSize))
+		ccw := float64(cell.Column) * theta
+		cw := float64(cell.Column+1) * theta
+
+		if !cell.Linked(cell.Inward) {
+			arc(inner, ccw, cw)
+		}
+		if !cell.Linked(cell.Clockwise) {
+			x0, y0 := point(inner, cw)
+			x1, y1 := point(outer, cw)
+			drawLine(img, x0, y0, x1, y1, wallColor)
+		}
+	}
+	arc(float64(g.Rows*int64(cellSize)), 0, 2*math.Pi)
+
+	return png.Encode(w, img)
+}
@@ -0,0 +1,157 @@
+package maze
+
+import (
+	"bytes"
+	"image/png"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestNewPolarGrid(t *testing.T) {
+	tests := []struct {
+		rings  int64
+		counts []int
+	}{
+		{0, []int{}},
+		{1, []int{1}},
+		{2, []int{1, 6}},
+		{4, []int{1, 6, 12, 24}},
+		{8, []int{1, 6, 12, 24, 24, 24, 48, 48}},
+	}
+	for _, tc := range tests {
+		g, err := NewPolarGrid(tc.rings)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts := []int{}
+		size := int64(0)
+		for r, row := range g.grid {
+			counts = append(counts, len(row))
+			size += int64(len(row))
+			if r > 0 && len(row)%len(g.grid[r-1]) != 0 {
+				t.Errorf("ring %d has %d cells, which isn't a multiple of %d", r, len(row), len(g.grid[r-1]))
+			}
+		}
+		if !reflect.DeepEqual(counts, tc.counts) || g.Size() != size {
+			t.Errorf("NewPolarGrid(%d) has rings of %v with size %d, want %v", tc.rings, counts, g.Size(), tc.counts)
+		}
+	}
+	if _, err := NewPolarGrid(-1); err == nil {
+		t.Error("NewPolarGrid(-1) returned no error")
+	}
+}
+
+func TestPolarNeighbors(t *testing.T) {
+	g, err := NewPolarGrid(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cell := range g.Cells() {
+		if cell.Row == 0 {
+			if len(cell.Neighbors()) != len(g.grid[1]) {
+				t.Errorf("the center has %d neighbors, want %d", len(cell.Neighbors()), len(g.grid[1]))
+			}
+			continue
+		}
+		if cell.Clockwise.CounterClockwise != cell || cell.CounterClockwise.Clockwise != cell {
+			t.Errorf("cell [%d, %d] isn't its neighbors' neighbor within its ring", cell.Row, cell.Column)
+		}
+		if !containsPolarCell(cell.Inward.Outward, cell) {
+			t.Errorf("cell [%d, %d] isn't outward of its inward neighbor", cell.Row, cell.Column)
+		}
+		for _, n := range cell.Outward {
+			if n.Inward != cell {
+				t.Errorf("cell [%d, %d] isn't inward of its outward neighbor", cell.Row, cell.Column)
+			}
+		}
+		if cell.Row == g.Rows-1 && len(cell.Outward) != 0 {
+			t.Errorf("cell [%d, %d] of the outer ring has outward neighbors", cell.Row, cell.Column)
+		}
+	}
+}
+
+// containsPolarCell returns true if a cell appears in a list of cells
+func containsPolarCell(cells []*PolarCell, c *PolarCell) bool {
+	for _, x := range cells {
+		if x == c {
+			return true
+		}
+	}
+	return false
+}
+
+func TestPolarLink(t *testing.T) {
+	g, err := NewPolarGrid(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := g.At(1, 0), g.At(1, 1)
+	a.Link(b)
+	if !a.Linked(b) || !b.Linked(a) || a.Linked(nil) {
+		t.Error("Link() didn't link the cells in both directions")
+	}
+	a.Link(b)
+	if len(a.LinkedNodes()) != 1 {
+		t.Errorf("linking twice left %d links", len(a.LinkedNodes()))
+	}
+	b.Unlink(a)
+	if a.Linked(b) || b.Linked(a) {
+		t.Error("Unlink() didn't unlink the cells in both directions")
+	}
+}
+
+func TestPolarMaze(t *testing.T) {
+	for seed := int64(0); seed < 5; seed++ {
+		g, err := NewPolarGrid(8)
+		if err != nil {
+			t.Fatal(err)
+		}
+		RecursiveBacktrackerGraph(g, rand.New(rand.NewSource(seed)))
+		if !graphIsPerfect(g) {
+			t.Fatalf("seed %d: maze is not perfect", seed)
+		}
+		dist := GraphDistances(g.At(0, 0))
+		for _, cell := range g.Cells() {
+			for _, n := range cell.LinkedNodes() {
+				if d := dist[n] - dist[cell]; d != 1 && d != -1 {
+					t.Fatalf("seed %d: linked cells are %d steps apart from the center", seed, d)
+				}
+			}
+		}
+
+		var out bytes.Buffer
+		if err := g.ToPNG(&out, 5); err != nil {
+			t.Fatal(err)
+		}
+		img, err := png.Decode(&out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if size := img.Bounds().Size(); size.X != 81 || size.Y != 81 {
+			t.Errorf("image is %v, want 81x81", size)
+		}
+	}
+}
+
+func TestPolarRandomCell(t *testing.T) {
+	empty, err := NewPolarGrid(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := empty.RandomCellRand(rand.New(rand.NewSource(1))); c != nil {
+		t.Errorf("RandomCellRand() of an empty grid = [%d, %d], want nil", c.Row, c.Column)
+	}
+	if n := empty.RandomNode(rand.New(rand.NewSource(1))); n != nil {
+		t.Errorf("RandomNode() of an empty grid = %v, want nil", n)
+	}
+
+	g, err := NewPolarGrid(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := g.RandomCellRand(rand.New(rand.NewSource(3))), g.RandomCellRand(rand.New(rand.NewSource(3)))
+	if a != b {
+		t.Errorf("RandomCellRand() with the same seed chose [%d, %d] and [%d, %d]", a.Row, a.Column, b.Row, b.Column)
+	}
+}
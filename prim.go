@@ -0,0 +1,47 @@
+package maze
+
+import (
+	"math/rand"
+)
+
+// RandomizedPrim uses a randomized version of Prim's algorithm to create a maze
+// in a rectangular grid.  The maze grows outward from a single cell: a random
+// wall on the edge of the maze is chosen, and if the cell beyond it hasn't been
+// visited the wall is removed.  The result has a bushy texture with lots of
+// branching
+func RandomizedPrim(g *Grid) {
+	RandomizedPrimRand(g, defaultRand)
+}
+
+// RandomizedPrimRand is RandomizedPrim using the provided random source
+func RandomizedPrimRand(g *Grid, r *rand.Rand) {
+	if g.Size() == 0 {
+		return
+	}
+
+	// Each frontier entry is a wall between a visited cell and its neighbor
+	frontier := [][2]*Cell{}
+	visited := map[*Cell]bool{}
+	visit := func(cell *Cell) {
+		visited[cell] = true
+		for _, n := range cell.Neighbors() {
+			if !visited[n] {
+				frontier = append(frontier, [2]*Cell{cell, n})
+			}
+		}
+	}
+	visit(g.RandomCellRand(r))
+
+	for len(frontier) > 0 {
+		// Remove a random wall by swapping it with the last one
+		i := r.Intn(len(frontier))
+		wall := frontier[i]
+		frontier[i] = frontier[len(frontier)-1]
+		frontier = frontier[:len(frontier)-1]
+
+		if !visited[wall[1]] {
+			wall[0].Link(wall[1])
+			visit(wall[1])
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package maze
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestRandomizedPrim(t *testing.T) {
+	sizes := []struct {
+		rows, columns int64
+	}{
+		{1, 1},
+		{1, 10},
+		{10, 1},
+		{12, 12},
+		{5, 16},
+	}
+	for _, s := range sizes {
+		for seed := int64(0); seed < 5; seed++ {
+			g := NewGrid(s.rows, s.columns)
+			RandomizedPrimRand(&g, rand.New(rand.NewSource(seed)))
+			if reached := int64(len(Reachable(g.At(0, 0)))); reached != g.Size() {
+				t.Errorf("%dx%d seed %d: %d of %d cells are connected:\n%s", s.rows, s.columns, seed, reached, g.Size(), g.ToString())
+			}
+			// A passage is carved each time a cell is visited, apart from the
+			// first, so visiting a cell twice would carve extra passages
+			ends := 0
+			for _, cell := range g.Cells() {
+				ends += cell.linkCount()
+			}
+			if int64(ends/2) != g.Size()-1 {
+				t.Errorf("%dx%d seed %d: carved %d passages for %d cells", s.rows, s.columns, seed, ends/2, g.Size())
+			}
+		}
+	}
+}
+
+func TestRandomizedPrimMaskedRegions(t *testing.T) {
+	// Only the region containing the first cell visited can be reached
+	m := maskFromString(t, "...X..\n...X..")
+	for seed := int64(0); seed < 10; seed++ {
+		g := NewMaskedGrid(m)
+		RandomizedPrimRand(g, rand.New(rand.NewSource(seed)))
+		left, right := len(Reachable(g.At(0, 0))), len(Reachable(g.At(0, 4)))
+		if !(left == 6 && right == 1) && !(left == 1 && right == 4) {
+			t.Errorf("seed %d: regions of %d and %d cells are connected, want one region carved:\n%s", seed, left, right, g.ToString())
+		}
+		if len(Cycles(g)) != 0 {
+			t.Errorf("seed %d: maze has loops:\n%s", seed, g.ToString())
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package maze
+
+import (
+	"math/rand"
+)
+
+// globalSource is a random source backed by the top-level math/rand functions,
+// so that generators which accept a *rand.Rand can share the default source
+type globalSource struct{}
+
+// Int63 returns a non-negative pseudo-random 63-bit integer from the default
+// source
+func (globalSource) Int63() int64 {
+	return rand.Int63()
+}
+
+// Uint64 returns a pseudo-random 64-bit integer from the default source
+func (globalSource) Uint64() uint64 {
+	return rand.Uint64()
+}
+
+// Seed seeds the default source
+func (globalSource) Seed(seed int64) {
+	rand.Seed(seed)
+}
+
+// defaultRand draws from the default source used by the top-level math/rand
+// functions
+var defaultRand = rand.New(globalSource{})
@@ -0,0 +1,87 @@
+package maze
+
+import (
+	"math"
+)
+
+const (
+	// maxRandomWalkCells is the largest number of cells for which the expected
+	// length of a random walk is computed.  The linear system grows with the
+	// square of this number, and solving it with its cube
+	maxRandomWalkCells = 1000
+)
+
+// ExpectedRandomWalkSteps returns the expected number of steps a walker who
+// picks a random passage at every cell takes to travel from start to goal.
+// It solves the linear system of hitting times h(goal) = 0 and
+// h(c) = 1 + the average of h over the cells linked to c, so its cost grows
+// with the cube of the number of cells reachable from start.  If more than 1000
+// cells are reachable, the result is NaN.  If the goal can't be reached, the
+// result is +Inf
+func ExpectedRandomWalkSteps(g *Grid, start, goal *Cell) float64 {
+	distances := ComputeDistances(start)
+	if _, ok := distances.Get(goal); !ok {
+		return math.Inf(1)
+	}
+	if start == goal {
+		return 0
+	}
+	if len(distances.order) > maxRandomWalkCells {
+		return math.NaN()
+	}
+
+	// Number every reachable cell except the goal
+	index := map[*Cell]int{}
+	for _, cell := range distances.order {
+		if cell != goal {
+			index[cell] = len(index)
+		}
+	}
+	n := len(index)
+	// Each row holds the coefficients of one equation followed by its constant
+	m := make([][]float64, n)
+	for cell, i := range index {
+		m[i] = make([]float64, n+1)
+		links := cell.Links()
+		m[i][i] = float64(len(links))
+		m[i][n] = float64(len(links))
+		for _, l := range links {
+			if j, ok := index[l]; ok {
+				m[i][j]--
+			}
+		}
+	}
+
+	// Gaussian elimination with partial pivoting
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(m[row][col]) > math.Abs(m[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(m[pivot][col]) < 1e-12 {
+			// Some cells can't reach the goal at all
+			return math.Inf(1)
+		}
+		m[col], m[pivot] = m[pivot], m[col]
+		for row := col + 1; row < n; row++ {
+			factor := m[row][col] / m[col][col]
+			if factor == 0 {
+				continue
+			}
+			for k := col; k <= n; k++ {
+				m[row][k] -= factor * m[col][k]
+			}
+		}
+	}
+	h := make([]float64, n)
+	for row := n - 1; row >= 0; row-- {
+		sum := m[row][n]
+		for k := row + 1; k < n; k++ {
+			sum -= m[row][k] * h[k]
+		}
+		h[row] = sum / m[row][row]
+	}
+	return h[index[start]]
+}
@@ -0,0 +1,44 @@
+package maze
+
+import (
+	"math"
+	"testing"
+)
+
+func TestExpectedRandomWalkSteps(t *testing.T) {
+	loop := linkedGrid(2, 2, [][4]int64{{0, 0, 0, 1}, {0, 1, 1, 1}, {1, 1, 1, 0}, {1, 0, 0, 0}})
+	tests := []struct {
+		name        string
+		grid        *Grid
+		start, goal [2]int64
+		want        float64
+	}{
+		{"SameCell", serpentine(1, 5), [2]int64{0, 2}, [2]int64{0, 2}, 0},
+		// Crossing a corridor of n cells from end to end takes (n-1)^2 steps
+		{"CorridorEnds", serpentine(1, 5), [2]int64{0, 0}, [2]int64{0, 4}, 16},
+		{"CorridorMiddle", serpentine(1, 5), [2]int64{0, 2}, [2]int64{0, 4}, 12},
+		{"LongCorridor", serpentine(4, 5), [2]int64{0, 0}, [2]int64{3, 0}, 19 * 19},
+		// On a loop of n cells, reaching a cell k steps away takes k(n-k) steps
+		{"LoopAdjacent", loop, [2]int64{0, 0}, [2]int64{0, 1}, 3},
+		{"LoopOpposite", loop, [2]int64{0, 0}, [2]int64{1, 1}, 4},
+		{"Unreachable", linkedGrid(1, 3, [][4]int64{{0, 0, 0, 1}}), [2]int64{0, 0}, [2]int64{0, 2}, math.Inf(1)},
+		{"TooLarge", serpentine(40, 30), [2]int64{0, 0}, [2]int64{0, 1}, math.NaN()},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ExpectedRandomWalkSteps(tc.grid, tc.grid.At(tc.start[0], tc.start[1]), tc.grid.At(tc.goal[0], tc.goal[1]))
+			switch {
+			case math.IsNaN(tc.want):
+				if !math.IsNaN(got) {
+					t.Errorf("ExpectedRandomWalkSteps() = %v, want NaN", got)
+				}
+			case math.IsInf(tc.want, 1):
+				if !math.IsInf(got, 1) {
+					t.Errorf("ExpectedRandomWalkSteps() = %v, want +Inf", got)
+				}
+			case math.Abs(got-tc.want) > 1e-9*math.Max(1, tc.want):
+				t.Errorf("ExpectedRandomWalkSteps() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package maze
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestGeneratorsAreDeterministic(t *testing.T) {
+	cases := append([]generatorCase{
+		{name: "BinaryTreeBiased", generate: func(g *Grid, r *rand.Rand) { BinaryTreeBiasedRand(g, South, West, r) }},
+		{name: "Braid", generate: func(g *Grid, r *rand.Rand) { RecursiveBacktrackerRand(g, r); Braid(g, 0.5, r) }},
+		{name: "HybridMaze", generate: func(g *Grid, r *rand.Rand) { HybridMaze(g, WilsonsRand, EllersRand, 4, r) }},
+		{name: "SparseBacktracker", generate: func(g *Grid, r *rand.Rand) { SparseBacktracker(g, 0.5, r) }},
+	}, generators...)
+	for _, gen := range cases {
+		t.Run(gen.name, func(t *testing.T) {
+			first, second := NewGrid(8, 10), NewGrid(8, 10)
+			gen.generate(&first, rand.New(rand.NewSource(42)))
+			gen.generate(&second, rand.New(rand.NewSource(42)))
+			if a, b := first.ToString(), second.ToString(); a != b {
+				t.Errorf("the same seed produced different mazes:\n%s\n%s", a, b)
+			}
+		})
+	}
+}
+
+func TestRandomCellRandIsDeterministic(t *testing.T) {
+	g := NewMaskedGrid(maskFromString(t, "X....\n..X..\n....X"))
+	a, b := rand.New(rand.NewSource(3)), rand.New(rand.NewSource(3))
+	for i := 0; i < 20; i++ {
+		ca, cb := g.RandomCellRand(a), g.RandomCellRand(b)
+		if ca != cb {
+			t.Fatalf("draw %d: [%d, %d] != [%d, %d]", i, ca.Row, ca.Column, cb.Row, cb.Column)
+		}
+		if ca == nil {
+			t.Fatalf("draw %d returned a disabled cell", i)
+		}
+	}
+}
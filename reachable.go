@@ -0,0 +1,201 @@
+package maze
+
+// Reachable returns the set of cells which can be reached from a starting cell
+// by following links, including the starting cell.  This can be used to check
+// whether the exit of a maze can be reached
+func Reachable(from *Cell) map[*Cell]bool {
+	return reachableVia(from, (*Cell).Links)
+}
+
+// region returns the set of cells which can be reached from a starting cell by
+// moving between neighbors, whether or not they are linked.  On a masked grid
+// this is the part of the enabled area which contains the cell
+func region(from *Cell) map[*Cell]bool {
+	return reachableVia(from, (*Cell).Neighbors)
+}
+
+// reachableVia returns the set of cells which can be reached from a starting
+// cell by moving from each cell to the cells next returns
+func reachableVia(from *Cell, next func(*Cell) []*Cell) map[*Cell]bool {
+	seen := map[*Cell]bool{from: true}
+	frontier := []*Cell{from}
+	for len(frontier) > 0 {
+		cell := frontier[0]
+		frontier = frontier[1:]
+		for _, n := range next(cell) {
+			if !seen[n] {
+				seen[n] = true
+				frontier = append(frontier, n)
+			}
+		}
+	}
+	return seen
+}
+
+// ReachableBounds returns the bounding box of all cells which can be reached
+// from root.  If root is not a cell in the grid, all of the bounds are -1
+func ReachableBounds(g *Grid, root *Cell) (minRow, minCol, maxRow, maxCol int64) {
+	if root == nil || g.At(root.Row, root.Column) != root {
+		return -1, -1, -1, -1
+	}
+
+	minRow, minCol, maxRow, maxCol = root.Row, root.Column, root.Row, root.Column
+	for cell := range Reachable(root) {
+		if cell.Row < minRow {
+			minRow = cell.Row
+		}
+		if cell.Row > maxRow {
+			maxRow = cell.Row
+		}
+		if cell.Column < minCol {
+			minCol = cell.Column
+		}
+		if cell.Column > maxCol {
+			maxCol = cell.Column
+		}
+	}
+	return minRow, minCol, maxRow, maxCol
+}
+
+// components returns the groups of cells which are connected to each other by
+// links.  Components are ordered by the position of their first cell, and the
+// cells within each component are ordered by their distance from that cell
+func components(g *Grid) [][]*Cell {
+	seen := map[*Cell]bool{}
+	ret := [][]*Cell{}
+	for _, cell := range g.Cells() {
+		if seen[cell] {
+			continue
+		}
+		seen[cell] = true
+		component := []*Cell{cell}
+		for i := 0; i < len(component); i++ {
+			for _, n := range component[i].Links() {
+				if !seen[n] {
+					seen[n] = true
+					component = append(component, n)
+				}
+			}
+		}
+		ret = append(ret, component)
+	}
+	return ret
+}
+
+// RegionCount returns the number of groups of cells which are connected to each
+// other by links.  A perfect maze has a single region, while a grid with no
+// links has one region per cell
+func RegionCount(g *Grid) int {
+	return len(components(g))
+}
+
+// DisconnectedLoops returns the cycles found in every connected component other
+// than the largest one.  Each cycle is reported as the list of cells around it.
+// A correctly generated maze contains a single component, so any result
+// indicates a generator error
+func DisconnectedLoops(g *Grid) [][]*Cell {
+	comps := components(g)
+	largest := 0
+	for i, comp := range comps {
+		if len(comp) > len(comps[largest]) {
+			largest = i
+		}
+	}
+
+	loops := [][]*Cell{}
+	for i, comp := range comps {
+		if i != largest {
+			loops = append(loops, componentLoops(comp)...)
+		}
+	}
+	return loops
+}
+
+// Cycles returns the links which close loops in the maze: every link beyond
+// those needed to connect the cells of each region.  Links are considered in
+// grid order, so each reported link is the one which completed its loop.  A
+// perfect maze has none.  Links to cells outside the grid are ignored
+func Cycles(g *Grid) [][2]*Cell {
+	cells := g.Cells()
+	sets := newUnionFind(cells)
+	ret := [][2]*Cell{}
+	for _, cell := range cells {
+		for _, l := range cell.Links() {
+			if g.At(l.Row, l.Column) != l || g.cellID(l) <= g.cellID(cell) {
+				continue
+			}
+			if !sets.union(cell, l) {
+				ret = append(ret, [2]*Cell{cell, l})
+			}
+		}
+	}
+	return ret
+}
+
+// componentLoops returns one cycle for each link in a connected component which
+// is not part of a breadth-first spanning tree of that component.  The cells of
+// the component must be in breadth-first order from the first cell
+func componentLoops(comp []*Cell) [][]*Cell {
+	index := map[*Cell]int{}
+	for i, cell := range comp {
+		index[cell] = i
+	}
+
+	// Rebuild the spanning tree which produced the breadth-first order
+	parent := map[*Cell]*Cell{}
+	depth := map[*Cell]int{comp[0]: 0}
+	for _, cell := range comp {
+		for _, n := range cell.Links() {
+			if _, ok := depth[n]; !ok {
+				parent[n] = cell
+				depth[n] = depth[cell] + 1
+			}
+		}
+	}
+
+	loops := [][]*Cell{}
+	for _, a := range comp {
+		for _, b := range a.Links() {
+			if index[a] >= index[b] || parent[b] == a || parent[a] == b {
+				continue
+			}
+			// Walk both ends up the tree until they meet
+			left, right := []*Cell{a}, []*Cell{b}
+			x, y := a, b
+			for x != y {
+				if depth[x] >= depth[y] {
+					x = parent[x]
+					left = append(left, x)
+				} else {
+					y = parent[y]
+					right = append(right, y)
+				}
+			}
+			// The meeting point is the last entry of both walks
+			loop := left
+			for j := len(right) - 2; j >= 0; j-- {
+				loop = append(loop, right[j])
+			}
+			loops = append(loops, loop)
+		}
+	}
+	return loops
+}
+
+// ReachableWithinSteps returns the cells a player starting at start can visit
+// and return from using at most budget steps in total: those whose distance from
+// start is no more than half the budget.  If start isn't a cell of this grid, no
+// cells are returned
+func ReachableWithinSteps(g *Grid, start *Cell, budget int) map[*Cell]bool {
+	cells := map[*Cell]bool{}
+	if start == nil || g.At(start.Row, start.Column) != start {
+		return cells
+	}
+	distances := ComputeDistances(start)
+	for _, cell := range distances.order {
+		if d, _ := distances.Get(cell); 2*d <= int64(budget) {
+			cells[cell] = true
+		}
+	}
+	return cells
+}
@@ -0,0 +1,220 @@
+package maze
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// sortedPositions returns the row and column of each cell in a set, in
+// row-major order
+func sortedPositions(cells map[*Cell]bool) [][2]int64 {
+	list := []*Cell{}
+	for c := range cells {
+		list = append(list, c)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Row != list[j].Row {
+			return list[i].Row < list[j].Row
+		}
+		return list[i].Column < list[j].Column
+	})
+	return positions(list)
+}
+
+func TestReachable(t *testing.T) {
+	tests := []struct {
+		name string
+		grid *Grid
+		from [2]int64
+		want [][2]int64
+	}{
+		{"Unlinked", linkedGrid(2, 2, nil), [2]int64{1, 1}, [][2]int64{{1, 1}}},
+		{"Perfect", serpentine(2, 2), [2]int64{1, 0}, [][2]int64{{0, 0}, {0, 1}, {1, 0}, {1, 1}}},
+		{"Partial", linkedGrid(2, 3, [][4]int64{{0, 0, 0, 1}, {0, 1, 1, 1}, {0, 2, 1, 2}}), [2]int64{1, 1}, [][2]int64{{0, 0}, {0, 1}, {1, 1}}},
+		{"OtherPart", linkedGrid(2, 3, [][4]int64{{0, 0, 0, 1}, {0, 1, 1, 1}, {0, 2, 1, 2}}), [2]int64{0, 2}, [][2]int64{{0, 2}, {1, 2}}},
+		{"NonNeighborLink", linkedGrid(1, 4, [][4]int64{{0, 0, 0, 3}}), [2]int64{0, 3}, [][2]int64{{0, 0}, {0, 3}}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := sortedPositions(Reachable(tc.grid.At(tc.from[0], tc.from[1]))); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Reachable() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestReachableOneWay(t *testing.T) {
+	g := NewGrid(1, 3)
+	g.At(0, 0).LinkOneWay(g.At(0, 1))
+	g.At(0, 1).LinkOneWay(g.At(0, 2))
+	tests := []struct {
+		from int64
+		want [][2]int64
+	}{
+		{0, [][2]int64{{0, 0}, {0, 1}, {0, 2}}},
+		{1, [][2]int64{{0, 1}, {0, 2}}},
+		{2, [][2]int64{{0, 2}}},
+	}
+	for _, tc := range tests {
+		if got := sortedPositions(Reachable(g.At(0, tc.from))); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Reachable([0, %d]) = %v, want %v", tc.from, got, tc.want)
+		}
+	}
+}
+
+func TestCycles(t *testing.T) {
+	square := [][4]int64{{0, 0, 0, 1}, {0, 1, 1, 1}, {1, 1, 1, 0}, {1, 0, 0, 0}}
+	tests := []struct {
+		name string
+		grid *Grid
+		want [][2][2]int64
+	}{
+		{"Empty", linkedGrid(0, 0, nil), [][2][2]int64{}},
+		{"Unlinked", linkedGrid(2, 2, nil), [][2][2]int64{}},
+		{"Perfect", serpentine(3, 3), [][2][2]int64{}},
+		// The last link of the square is found from its lower-left corner
+		{"Square", linkedGrid(2, 2, square), [][2][2]int64{{{1, 0}, {1, 1}}}},
+		{"OpenGrid", func() *Grid { g := NewFullyLinkedGrid(2, 3); return &g }(), [][2][2]int64{{{1, 0}, {1, 1}}, {{1, 1}, {1, 2}}}},
+		{"SeparateSquares", linkedGrid(2, 5, append(square, [][4]int64{{0, 3, 0, 4}, {0, 4, 1, 4}, {1, 4, 1, 3}, {1, 3, 0, 3}}...)), [][2][2]int64{{{1, 0}, {1, 1}}, {{1, 3}, {1, 4}}}},
+		{"NonNeighborLink", linkedGrid(1, 3, [][4]int64{{0, 0, 0, 1}, {0, 1, 0, 2}, {0, 0, 0, 2}}), [][2][2]int64{{{0, 1}, {0, 2}}}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := [][2][2]int64{}
+			for _, link := range Cycles(tc.grid) {
+				got = append(got, [2][2]int64{{link[0].Row, link[0].Column}, {link[1].Row, link[1].Column}})
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Cycles() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestReachableBounds(t *testing.T) {
+	oneWay := NewGrid(3, 3)
+	oneWay.At(1, 1).LinkOneWay(oneWay.At(1, 2))
+	oneWay.At(1, 1).Link(oneWay.At(2, 1))
+	oneWay.At(1, 2).Link(oneWay.At(0, 2))
+	masked := NewMaskedGrid(maskFromString(t, "..\nX."))
+	masked.At(0, 0).Link(masked.At(0, 1))
+	masked.At(0, 1).Link(masked.At(1, 1))
+	tests := []struct {
+		name string
+		grid *Grid
+		root [2]int64
+		want [4]int64
+	}{
+		{"Perfect", serpentine(3, 4), [2]int64{1, 2}, [4]int64{0, 0, 2, 3}},
+		// The linked pair in the bottom right corner is an island which can't be reached
+		{"Island", linkedGrid(4, 4, [][4]int64{{0, 0, 0, 1}, {0, 1, 1, 1}, {1, 1, 1, 2}, {3, 2, 3, 3}}), [2]int64{0, 0}, [4]int64{0, 0, 1, 2}},
+		{"InsideIsland", linkedGrid(4, 4, [][4]int64{{0, 0, 0, 1}, {0, 1, 1, 1}, {1, 1, 1, 2}, {3, 2, 3, 3}}), [2]int64{3, 3}, [4]int64{3, 2, 3, 3}},
+		{"OneWay", &oneWay, [2]int64{1, 1}, [4]int64{0, 1, 2, 2}},
+		{"OneWayBack", &oneWay, [2]int64{0, 2}, [4]int64{0, 2, 1, 2}},
+		{"Unlinked", linkedGrid(2, 2, nil), [2]int64{1, 0}, [4]int64{1, 0, 1, 0}},
+		{"Masked", masked, [2]int64{1, 1}, [4]int64{0, 0, 1, 1}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			minRow, minCol, maxRow, maxCol := ReachableBounds(tc.grid, tc.grid.At(tc.root[0], tc.root[1]))
+			if got := [4]int64{minRow, minCol, maxRow, maxCol}; got != tc.want {
+				t.Errorf("ReachableBounds() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestReachableBoundsOutsideGrid(t *testing.T) {
+	g := NewGrid(3, 3)
+	other := NewGrid(3, 3)
+	for _, root := range []*Cell{nil, other.At(1, 1)} {
+		minRow, minCol, maxRow, maxCol := ReachableBounds(&g, root)
+		if got := [4]int64{minRow, minCol, maxRow, maxCol}; got != [4]int64{-1, -1, -1, -1} {
+			t.Errorf("ReachableBounds() = %v, want all -1", got)
+		}
+	}
+}
+
+func TestDisconnectedLoops(t *testing.T) {
+	// A winding component of eleven cells, with a 2x2 island in the corner
+	main := [][4]int64{{0, 0, 0, 1}, {0, 1, 0, 2}, {0, 2, 0, 3}, {0, 3, 0, 4},
+		{0, 0, 1, 0}, {1, 0, 2, 0}, {2, 0, 2, 1}, {2, 1, 2, 2}, {2, 2, 1, 2}, {1, 2, 1, 1}}
+	island := [][4]int64{{1, 3, 1, 4}, {1, 4, 2, 4}, {2, 4, 2, 3}, {2, 3, 1, 3}}
+	islandCells := [][2]int64{{1, 3}, {1, 4}, {2, 3}, {2, 4}}
+	tests := []struct {
+		name  string
+		links [][4]int64
+		want  [][][2]int64
+	}{
+		{"Perfect", main, [][][2]int64{}},
+		{"LoopIsland", append(append([][4]int64{}, main...), island...), [][][2]int64{islandCells}},
+		// Loops in the largest component are not reported
+		{"MainLoop", append(append([][4]int64{}, main...), [4]int64{1, 0, 1, 1}), [][][2]int64{}},
+		{"TreeIsland", append(append([][4]int64{}, main...), island[:3]...), [][][2]int64{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := [][][2]int64{}
+			for _, loop := range DisconnectedLoops(linkedGrid(3, 5, tc.links)) {
+				cells := map[*Cell]bool{}
+				for i, c := range loop {
+					cells[c] = true
+					if next := loop[(i+1)%len(loop)]; !c.Linked(next) {
+						t.Errorf("loop %v steps through a wall", positions(loop))
+					}
+				}
+				if len(cells) != len(loop) {
+					t.Errorf("loop %v visits a cell twice", positions(loop))
+				}
+				got = append(got, sortedPositions(cells))
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("DisconnectedLoops() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestReachableWithinSteps(t *testing.T) {
+	// A corridor along the top row with teeth hanging from it, and several
+	// cells which can't be reached at all
+	g := comb()
+	start := g.At(0, 0)
+	distances := ComputeDistances(start)
+	tests := []struct {
+		budget int
+		want   [][2]int64
+	}{
+		{-1, [][2]int64{}},
+		{0, [][2]int64{{0, 0}}},
+		{1, [][2]int64{{0, 0}}},
+		{2, [][2]int64{{0, 0}, {0, 1}}},
+		{5, [][2]int64{{0, 0}, {0, 1}, {0, 2}, {1, 1}}},
+	}
+	for _, tc := range tests {
+		got := ReachableWithinSteps(g, start, tc.budget)
+		if positions := sortedPositions(got); !reflect.DeepEqual(positions, tc.want) {
+			t.Errorf("ReachableWithinSteps(%d) = %v, want %v", tc.budget, positions, tc.want)
+		}
+	}
+	for budget := 0; budget < 20; budget++ {
+		got := ReachableWithinSteps(g, start, budget)
+		for _, cell := range g.Cells() {
+			d, ok := distances.Get(cell)
+			if want := ok && 2*d <= int64(budget); got[cell] != want {
+				t.Errorf("ReachableWithinSteps(%d) includes [%d, %d] = %v, but it is %d steps away", budget, cell.Row, cell.Column, got[cell], d)
+			}
+		}
+	}
+}
+
+func TestReachableWithinStepsOutsideGrid(t *testing.T) {
+	g := serpentine(3, 3)
+	other := serpentine(3, 3)
+	for _, start := range []*Cell{nil, other.At(1, 1)} {
+		if got := ReachableWithinSteps(g, start, 10); len(got) != 0 {
+			t.Errorf("ReachableWithinSteps() = %v, want no cells", sortedPositions(got))
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package maze
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// RecursiveBacktracker uses the recursive backtracker maze creation algorithm to
+// create a maze in a rectangular grid.  It carves a random walk through
+// unvisited cells, backing up along the walk whenever it reaches a dead end.
+// The walk is tracked with an explicit stack rather than by recursion, so large
+// grids don't exhaust the call stack.  The result has long, winding corridors
+// and few dead ends
+func RecursiveBacktracker(g *Grid) {
+	RecursiveBacktrackerRand(g, defaultRand)
+}
+
+// RecursiveBacktrackerRand is RecursiveBacktracker using the provided random
+// source
+func RecursiveBacktrackerRand(g *Grid, r *rand.Rand) {
+	if g.Size() == 0 {
+		return
+	}
+	recursiveBacktracker(g, g.RandomCellRand(r), r, nil)
+}
+
+// RecursiveBacktrackerCtx is RecursiveBacktracker, but stops early and returns
+// the context's error if ctx is cancelled.  The passages carved before then are
+// left in place
+func RecursiveBacktrackerCtx(ctx context.Context, g *Grid) error {
+	if g.Size() == 0 {
+		return nil
+	}
+	_, err := recursiveBacktrackerCtx(ctx, g, g.RandomCell(), defaultRand, nil, nil)
+	return err
+}
+
+// RecursiveBacktrackerObserved is RecursiveBacktracker, but calls onStep after
+// each passage is carved with the cell the passage leads to.  This can be used
+// to record the progress of the algorithm, such as to animate it
+func RecursiveBacktrackerObserved(g *Grid, onStep func(current *Cell)) {
+	if g.Size() == 0 {
+		return
+	}
+	_, _ = recursiveBacktrackerCtx(context.Background(), g, g.RandomCell(), defaultRand, nil, onStep)
+}
+
+// RecursiveBacktrackerFiltered uses the recursive backtracker algorithm to
+// create a maze, but only carves passages which allow permits.  This can be used
+// to impose structural rules on the maze.  If the rules leave some cells
+// unreachable, the reachable cells are still carved and an error is returned
+func RecursiveBacktrackerFiltered(g *Grid, allow func(from, to *Cell) bool, r *rand.Rand) error {
+	if g.Size() == 0 {
+		return nil
+	}
+	visited := recursiveBacktracker(g, g.RandomCellRand(r), r, allow)
+	if visited < g.Size() {
+		return fmt.Errorf("filter disconnects the grid: only %d of %d cells are reachable", visited, g.Size())
+	}
+	return nil
+}
+
+// SparseBacktracker uses the recursive backtracker algorithm to create a maze
+// which covers only part of the grid.  The walk stops once the given fraction
+// of the cells have been visited, leaving the remaining cells unlinked as solid
+// regions.  The visited cells form a single connected maze.  The density must
+// be greater than 0 and at most 1
+func SparseBacktracker(g *Grid, density float64, rng *rand.Rand) error {
+	if !(density > 0 && density <= 1) {
+		return fmt.Errorf("invalid density: %v", density)
+	}
+	if g.Size() == 0 {
+		return nil
+	}
+	target := int64(math.Ceil(density * float64(g.Size())))
+	visited := int64(1)
+	allow := func(from, to *Cell) bool {
+		return visited < target
+	}
+	onStep := func(*Cell) {
+		visited++
+	}
+	_, err := recursiveBacktrackerCtx(context.Background(), g, g.RandomCellRand(rng), rng, allow, onStep)
+	return err
+}
+
+// recursiveBacktracker carves a perfect maze with a depth-first random walk
+// which begins at start and backs up whenever it reaches a dead end.  A link is
+// only carved if allow permits it; a nil allow permits every link.  The number
+// of cells visited is returned
+func recursiveBacktracker(g *Grid, start *Cell, r *rand.Rand, allow func(from, to *Cell) bool) int64 {
+	visited, _ := recursiveBacktrackerCtx(context.Background(), g, start, r, allow, nil)
+	return visited
+}
+
+// recursiveBacktrackerCtx is recursiveBacktracker, but stops early and returns
+// the context's error if ctx is cancelled.  If onStep is provided, it is called
+// with each cell as soon as the cell is linked into the maze
+func recursiveBacktrackerCtx(ctx context.Context, g *Grid, start *Cell, r *rand.Rand, allow func(from, to *Cell) bool, onStep func(*Cell)) (int64, error) {
+	visited := map[*Cell]bool{start: true}
+	stack := []*Cell{start}
+	for steps := 0; len(stack) > 0; steps++ {
+		if steps%contextCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return int64(len(visited)), err
+			}
+		}
+		current := stack[len(stack)-1]
+		candidates := []*Cell{}
+		for _, n := range current.Neighbors() {
+			if !visited[n] && (allow == nil || allow(current, n)) {
+				candidates = append(candidates, n)
+			}
+		}
+
+		if len(candidates) == 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		next := candidates[r.Intn(len(candidates))]
+		current.Link(next)
+		visited[next] = true
+		stack = append(stack, next)
+		if onStep != nil {
+			onStep(next)
+		}
+	}
+	return int64(len(visited)), nil
+}
@@ -0,0 +1,127 @@
+package maze
+
+import (
+	"math/rand"
+	runtimedebug "runtime/debug"
+	"testing"
+)
+
+func TestRecursiveBacktrackerFiltered(t *testing.T) {
+	// inBox returns true for the cells of a 2x2 block in the middle of the grid
+	inBox := func(c *Cell) bool {
+		return c.Row >= 2 && c.Row <= 3 && c.Column >= 2 && c.Column <= 3
+	}
+	for seed := int64(0); seed < 10; seed++ {
+		g := NewGrid(6, 6)
+		err := RecursiveBacktrackerFiltered(&g, func(from, to *Cell) bool {
+			return !inBox(from) || !inBox(to)
+		}, rand.New(rand.NewSource(seed)))
+		if err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+		for _, cell := range g.Cells() {
+			for _, l := range cell.Links() {
+				if inBox(cell) && inBox(l) {
+					t.Errorf("seed %d: [%d, %d] and [%d, %d] are linked inside the box", seed, cell.Row, cell.Column, l.Row, l.Column)
+				}
+			}
+		}
+		if !IsPerfect(&g) {
+			t.Errorf("seed %d: maze is not perfect:\n%s", seed, g.ToString())
+		}
+	}
+}
+
+func TestRecursiveBacktrackerFilteredDisconnects(t *testing.T) {
+	// No passage may cross between the left and right halves
+	for seed := int64(0); seed < 10; seed++ {
+		g := NewGrid(4, 6)
+		err := RecursiveBacktrackerFiltered(&g, func(from, to *Cell) bool {
+			return (from.Column < 3) == (to.Column < 3)
+		}, rand.New(rand.NewSource(seed)))
+		if err == nil {
+			t.Fatalf("seed %d: RecursiveBacktrackerFiltered() returned no error", seed)
+		}
+		// The half the walk started in is still carved as a perfect maze
+		var start *Cell
+		for _, cell := range g.Cells() {
+			if cell.hasLinks() {
+				start = cell
+				break
+			}
+		}
+		if start == nil || len(Reachable(start)) != 12 || len(Cycles(&g)) != 0 {
+			t.Errorf("seed %d: want one half carved:\n%s", seed, g.ToString())
+		}
+	}
+}
+
+func TestRecursiveBacktracker(t *testing.T) {
+	sizes := []struct {
+		rows, columns int64
+	}{
+		{1, 1},
+		{1, 9},
+		{9, 1},
+		{12, 12},
+		{5, 17},
+	}
+	for _, s := range sizes {
+		for seed := int64(0); seed < 5; seed++ {
+			g := NewGrid(s.rows, s.columns)
+			RecursiveBacktrackerRand(&g, rand.New(rand.NewSource(seed)))
+			if !IsPerfect(&g) {
+				t.Errorf("%dx%d seed %d: maze is not perfect:\n%s", s.rows, s.columns, seed, g.ToString())
+			}
+		}
+	}
+}
+
+func TestRecursiveBacktrackerLarge(t *testing.T) {
+	// The walk on a 200x200 grid is tens of thousands of cells deep.  Limit the
+	// stack to far less than a recursive implementation would need, so that
+	// recursion would crash the test
+	defer runtimedebug.SetMaxStack(runtimedebug.SetMaxStack(256 << 10))
+	g := NewGrid(200, 200)
+	RecursiveBacktrackerRand(&g, rand.New(rand.NewSource(1)))
+	if !IsPerfect(&g) {
+		t.Fatal("200x200 maze is not perfect")
+	}
+	// Long winding corridors leave few dead ends
+	if ends := len(DeadEnds(&g)); 10*ends > int(g.Size()) {
+		t.Errorf("%d of %d cells are dead ends, want fewer than a tenth", ends, g.Size())
+	}
+}
+
+func TestRecursiveBacktrackerObserved(t *testing.T) {
+	g := NewMaskedGrid(maskFromString(t, "....\n.XX.\n...."))
+	// Every step carves a single passage from a cell already in the maze to a
+	// cell which was not
+	inMaze := map[*Cell]bool{}
+	steps := 0
+	RecursiveBacktrackerObserved(g, func(current *Cell) {
+		steps++
+		links := current.Links()
+		if len(links) != 1 {
+			t.Fatalf("step %d: [%d, %d] has %d links, want 1", steps, current.Row, current.Column, len(links))
+		}
+		if len(inMaze) == 0 {
+			inMaze[links[0]] = true
+		}
+		if inMaze[current] || !inMaze[links[0]] {
+			t.Errorf("step %d carved [%d, %d] to [%d, %d], want a passage into the maze from within it", steps, links[0].Row, links[0].Column, current.Row, current.Column)
+		}
+		inMaze[current] = true
+	})
+	if want := int(g.Size()) - 1; steps != want {
+		t.Errorf("observed %d steps, want %d", steps, want)
+	}
+	if !IsPerfect(g) {
+		t.Errorf("maze is not perfect:\n%s", g.ToString())
+	}
+
+	empty := NewGrid(0, 0)
+	RecursiveBacktrackerObserved(&empty, func(*Cell) {
+		t.Error("observed a step on an empty grid")
+	})
+}
@@ -0,0 +1,65 @@
+package maze
+
+import (
+	"math/rand"
+)
+
+// RecursiveDivision uses the recursive division maze creation algorithm to
+// create a maze in a rectangular grid.  Unlike the other algorithms it adds
+// walls rather than carving passages: every cell is first linked to all of its
+// neighbors, and then the grid is repeatedly divided in two by a wall with a
+// single gap in it until every region is one cell wide.  On a masked grid the
+// gap may fall on a disabled cell, so the result may be disconnected.  On a
+// cylinder or torus the joined edges are walled off, so no passage wraps around
+func RecursiveDivision(g *Grid) {
+	RecursiveDivisionRand(g, defaultRand)
+}
+
+// RecursiveDivisionRand is RecursiveDivision using the provided random source
+func RecursiveDivisionRand(g *Grid, r *rand.Rand) {
+	g.Reset()
+	// Passages across the joined edges of a cylinder or torus could never be
+	// cut by a dividing wall, so they would form loops
+	ForEachAdjacentPair(g, func(a, b *Cell) {
+		if !wrapsAround(a, b) {
+			a.Link(b)
+		}
+	})
+	divide(g, 0, 0, g.Rows, g.Columns, r)
+}
+
+// divide splits a region of the grid in two with a wall containing a single
+// gap, and then divides each half in turn
+func divide(g *Grid, row, column, height, width int64, r *rand.Rand) {
+	if height <= 1 || width <= 1 {
+		return
+	}
+
+	if height > width || (height == width && r.Intn(2) == 0) {
+		// Build a horizontal wall
+		divideSouthOf := r.Int63n(height - 1)
+		passageAt := r.Int63n(width)
+		for x := int64(0); x < width; x++ {
+			if x != passageAt {
+				if cell := g.At(row+divideSouthOf, column+x); cell != nil && cell.South != nil {
+					cell.Unlink(cell.South)
+				}
+			}
+		}
+		divide(g, row, column, divideSouthOf+1, width, r)
+		divide(g, row+divideSouthOf+1, column, height-divideSouthOf-1, width, r)
+	} else {
+		// Build a vertical wall
+		divideEastOf := r.Int63n(width - 1)
+		passageAt := r.Int63n(height)
+		for y := int64(0); y < height; y++ {
+			if y != passageAt {
+				if cell := g.At(row+y, column+divideEastOf); cell != nil && cell.East != nil {
+					cell.Unlink(cell.East)
+				}
+			}
+		}
+		divide(g, row, column, height, divideEastOf+1, r)
+		divide(g, row, column+divideEastOf+1, height, width-divideEastOf-1, r)
+	}
+}
@@ -0,0 +1,46 @@
+package maze
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestRecursiveDivision(t *testing.T) {
+	sizes := []struct {
+		rows, columns int64
+	}{
+		{1, 1},
+		{1, 10},
+		{10, 1},
+		{2, 2},
+		{11, 11},
+		{6, 14},
+	}
+	for _, s := range sizes {
+		for seed := int64(0); seed < 5; seed++ {
+			g := NewFullyLinkedGrid(s.rows, s.columns)
+			RecursiveDivisionRand(&g, rand.New(rand.NewSource(seed)))
+			if reached := int64(len(Reachable(g.At(0, 0)))); reached != g.Size() {
+				t.Errorf("%dx%d seed %d: %d of %d cells are connected:\n%s", s.rows, s.columns, seed, reached, g.Size(), g.ToString())
+			}
+			if !IsPerfect(&g) {
+				t.Errorf("%dx%d seed %d: maze is not perfect:\n%s", s.rows, s.columns, seed, g.ToString())
+			}
+		}
+	}
+}
+
+func TestRecursiveDivisionTorus(t *testing.T) {
+	for seed := int64(0); seed < 5; seed++ {
+		g := NewTorusGrid(6, 6)
+		RecursiveDivisionRand(&g, rand.New(rand.NewSource(seed)))
+		if !IsPerfect(&g) {
+			t.Errorf("seed %d: maze is not perfect:\n%s", seed, g.ToString())
+		}
+		ForEachAdjacentPair(&g, func(a, b *Cell) {
+			if wrapsAround(a, b) && a.Linked(b) {
+				t.Errorf("seed %d: passage [%d, %d]-[%d, %d] wraps around the grid", seed, a.Row, a.Column, b.Row, b.Column)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package maze
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Renderer writes a maze to an output stream in some format
+type Renderer interface {
+	Render(g *Grid, w io.Writer) error
+}
+
+// RendererFunc allows an ordinary function to be used as a Renderer
+type RendererFunc func(g *Grid, w io.Writer) error
+
+// Render calls f(g, w)
+func (f RendererFunc) Render(g *Grid, w io.Writer) error {
+	return f(g, w)
+}
+
+// Sizes used by the registered image renderers
+const (
+	defaultPNGCellSize      = 20
+	defaultPNGWallThickness = 2
+	defaultSVGCellSize      = 20
+)
+
+var (
+	renderersMu sync.RWMutex
+	renderers   = map[string]Renderer{}
+)
+
+// RegisterRenderer makes a renderer available under the given format name,
+// replacing any renderer previously registered with that name
+func RegisterRenderer(name string, r Renderer) {
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	renderers[name] = r
+}
+
+// RenderTo writes a maze to an output stream using the renderer registered for
+// the given format name
+func RenderTo(g *Grid, format string, w io.Writer) error {
+	renderersMu.RLock()
+	r, ok := renderers[format]
+	renderersMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("no renderer registered for format %q", format)
+	}
+	return r.Render(g, w)
+}
+
+func init() {
+	RegisterRenderer("ascii", RendererFunc(func(g *Grid, w io.Writer) error {
+		_, err := io.WriteString(w, g.ToString())
+		return err
+	}))
+	RegisterRenderer("dot", RendererFunc(func(g *Grid, w io.Writer) error {
+		return g.ToDOT(w)
+	}))
+	RegisterRenderer("html", RendererFunc(func(g *Grid, w io.Writer) error {
+		return g.ToHTML(w)
+	}))
+	RegisterRenderer("png", RendererFunc(func(g *Grid, w io.Writer) error {
+		return g.ToPNG(w, defaultPNGCellSize, defaultPNGWallThickness)
+	}))
+	RegisterRenderer("svg", RendererFunc(func(g *Grid, w io.Writer) error {
+		return g.ToSVG(w, defaultSVGCellSize)
+	}))
+}
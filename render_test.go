@@ -0,0 +1,63 @@
+package maze
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestRegisterRenderer(t *testing.T) {
+	g := NewGrid(2, 3)
+	RegisterRenderer("test-size", RendererFunc(func(g *Grid, w io.Writer) error {
+		_, err := fmt.Fprintf(w, "%dx%d", g.Rows, g.Columns)
+		return err
+	}))
+	var out bytes.Buffer
+	if err := RenderTo(&g, "test-size", &out); err != nil || out.String() != "2x3" {
+		t.Errorf("RenderTo() = %q, %v, want \"2x3\"", out.String(), err)
+	}
+
+	// A second registration replaces the first
+	RegisterRenderer("test-size", RendererFunc(func(g *Grid, w io.Writer) error {
+		_, err := fmt.Fprintf(w, "%d", g.Size())
+		return err
+	}))
+	out.Reset()
+	if err := RenderTo(&g, "test-size", &out); err != nil || out.String() != "6" {
+		t.Errorf("RenderTo() after replacing the renderer = %q, %v, want \"6\"", out.String(), err)
+	}
+}
+
+func TestRenderTo(t *testing.T) {
+	g := NewGrid(3, 3)
+	RecursiveBacktracker(&g)
+	tests := []struct {
+		format string
+		want   func(w io.Writer) error
+	}{
+		{"ascii", func(w io.Writer) error { _, err := io.WriteString(w, g.ToString()); return err }},
+		{"dot", g.ToDOT},
+		{"html", g.ToHTML},
+		{"png", func(w io.Writer) error { return g.ToPNG(w, defaultPNGCellSize, defaultPNGWallThickness) }},
+		{"svg", func(w io.Writer) error { return g.ToSVG(w, defaultSVGCellSize) }},
+	}
+	for _, tc := range tests {
+		var got, want bytes.Buffer
+		if err := RenderTo(&g, tc.format, &got); err != nil {
+			t.Errorf("RenderTo(%q) returned %v", tc.format, err)
+			continue
+		}
+		if err := tc.want(&want); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got.Bytes(), want.Bytes()) {
+			t.Errorf("RenderTo(%q) differs from the renderer it names", tc.format)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := RenderTo(&g, "no-such-format", &out); err == nil {
+		t.Error("RenderTo() with an unknown format returned no error")
+	}
+}
@@ -0,0 +1,59 @@
+package maze
+
+import (
+	"fmt"
+)
+
+// CarveRoom opens a rectangular room in the grid by linking every pair of
+// adjacent cells inside it.  It is typically applied after a maze has been
+// generated, which adds loops.  Each of the doorways must be a cell outside the
+// room next to one of its cells; it is linked to that cell to add an entrance
+func CarveRoom(g *Grid, topRow, leftCol, height, width int64, doorways ...*Cell) error {
+	if height < 1 || width < 1 || topRow < 0 || leftCol < 0 ||
+		topRow+height > g.Rows || leftCol+width > g.Columns {
+		return fmt.Errorf("room of %dx%d at [%d, %d] does not fit in a %dx%d grid",
+			height, width, topRow, leftCol, g.Rows, g.Columns)
+	}
+	inside := func(c *Cell) bool {
+		return c.Row >= topRow && c.Row < topRow+height &&
+			c.Column >= leftCol && c.Column < leftCol+width
+	}
+
+	for _, door := range doorways {
+		if door == nil || g.At(door.Row, door.Column) != door || inside(door) {
+			return fmt.Errorf("doorway must be a cell of the grid outside the room")
+		}
+		found := false
+		for _, n := range door.Neighbors() {
+			if inside(n) {
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("doorway [%d, %d] is not next to the room", door.Row, door.Column)
+		}
+	}
+
+	for r := topRow; r < topRow+height; r++ {
+		for c := leftCol; c < leftCol+width; c++ {
+			cell := g.At(r, c)
+			if cell == nil {
+				continue
+			}
+			for _, n := range []*Cell{cell.East, cell.South} {
+				if n != nil && inside(n) {
+					cell.Link(n)
+				}
+			}
+		}
+	}
+	for _, door := range doorways {
+		for _, n := range door.Neighbors() {
+			if inside(n) {
+				door.Link(n)
+				break
+			}
+		}
+	}
+	return nil
+}
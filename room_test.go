@@ -0,0 +1,92 @@
+package maze
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestCarveRoom(t *testing.T) {
+	g := NewGrid(4, 4)
+	if err := CarveRoom(&g, 1, 1, 2, 2, g.At(0, 1)); err != nil {
+		t.Fatal(err)
+	}
+	want := map[int64][]int64{
+		0: {}, 1: {5}, 2: {}, 3: {},
+		4: {}, 5: {1, 9, 6}, 6: {10, 5}, 7: {},
+		8: {}, 9: {5, 10}, 10: {6, 9}, 11: {},
+		12: {}, 13: {}, 14: {}, 15: {},
+	}
+	if got := g.AdjacencyList(); !reflect.DeepEqual(got, want) {
+		t.Errorf("AdjacencyList() = %v, want %v", got, want)
+	}
+}
+
+func TestCarveRoomMasked(t *testing.T) {
+	g := NewMaskedGrid(maskFromString(t, "...\n.X.\n..."))
+	if err := CarveRoom(g, 0, 0, 2, 2); err != nil {
+		t.Fatal(err)
+	}
+	want := map[int64][]int64{0: {3, 1}, 1: {0}, 2: {}, 3: {0}, 5: {}, 6: {}, 7: {}, 8: {}}
+	if got := g.AdjacencyList(); !reflect.DeepEqual(got, want) {
+		t.Errorf("AdjacencyList() = %v, want %v", got, want)
+	}
+}
+
+func TestCarveRoomInMaze(t *testing.T) {
+	g := NewGrid(10, 10)
+	RecursiveBacktrackerRand(&g, rand.New(rand.NewSource(1)))
+	if err := CarveRoom(&g, 3, 3, 3, 4); err != nil {
+		t.Fatal(err)
+	}
+	for r := int64(3); r < 6; r++ {
+		for c := int64(3); c < 7; c++ {
+			if cell := g.At(r, c); c < 6 && !cell.Linked(cell.East) || r < 5 && !cell.Linked(cell.South) {
+				t.Errorf("[%d, %d] is walled off inside the room", r, c)
+			}
+		}
+	}
+	if len(Reachable(g.At(0, 0))) != int(g.Size()) {
+		t.Error("carving a room disconnected the maze")
+	}
+	if IsPerfect(&g) {
+		t.Error("maze with a room is still perfect, want loops")
+	}
+}
+
+func TestCarveRoomErrors(t *testing.T) {
+	other := NewGrid(4, 4)
+	tests := []struct {
+		name                           string
+		topRow, leftCol, height, width int64
+		doorway                        func(g *Grid) *Cell
+	}{
+		{"NoHeight", 1, 1, 0, 2, nil},
+		{"NoWidth", 1, 1, 2, 0, nil},
+		{"NegativeRow", -1, 1, 2, 2, nil},
+		{"NegativeColumn", 1, -1, 2, 2, nil},
+		{"TooTall", 1, 1, 4, 2, nil},
+		{"TooWide", 1, 1, 2, 4, nil},
+		{"NilDoorway", 1, 1, 2, 2, func(g *Grid) *Cell { return nil }},
+		{"DoorwayInside", 1, 1, 2, 2, func(g *Grid) *Cell { return g.At(1, 1) }},
+		{"DoorwayApart", 1, 1, 2, 2, func(g *Grid) *Cell { return g.At(0, 0) }},
+		{"DoorwayOfAnotherGrid", 1, 1, 2, 2, func(g *Grid) *Cell { return other.At(0, 1) }},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGrid(4, 4)
+			doorways := []*Cell{}
+			if tc.doorway != nil {
+				doorways = append(doorways, g.At(0, 1), tc.doorway(&g))
+			}
+			if err := CarveRoom(&g, tc.topRow, tc.leftCol, tc.height, tc.width, doorways...); err == nil {
+				t.Error("CarveRoom() succeeded, want an error")
+			}
+			for _, cell := range g.Cells() {
+				if cell.hasLinks() {
+					t.Fatalf("[%d, %d] was carved despite the error", cell.Row, cell.Column)
+				}
+			}
+		})
+	}
+}
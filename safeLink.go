@@ -0,0 +1,64 @@
+package maze
+
+import (
+	"sync"
+)
+
+// linkLocks guards the links of cells which are linked concurrently.  Each cell
+// is guarded by the lock chosen by its position, so cells in the same position
+// of different grids share a lock
+var linkLocks [64]sync.Mutex
+
+// linkLock returns the lock which guards the links of a cell
+func linkLock(c *Cell) int {
+	i := (c.Row*31 + c.Column) % int64(len(linkLocks))
+	if i < 0 {
+		i += int64(len(linkLocks))
+	}
+	return int(i)
+}
+
+// lockPair locks the links of two cells, always acquiring the locks in the same
+// order so that concurrent callers cannot deadlock, and returns a function which
+// unlocks them
+func lockPair(a, b *Cell) func() {
+	i, j := linkLock(a), linkLock(b)
+	if i > j {
+		i, j = j, i
+	}
+	linkLocks[i].Lock()
+	if j != i {
+		linkLocks[j].Lock()
+	}
+	return func() {
+		if j != i {
+			linkLocks[j].Unlock()
+		}
+		linkLocks[i].Unlock()
+	}
+}
+
+// SafeLink links one cell to another bidirectionally like Link, but may be
+// called from several goroutines at once.  Every goroutine touching the same
+// cells must use SafeLink, SafeUnlink, and SafeLinked rather than the
+// unsynchronized methods
+func (c *Cell) SafeLink(neighbor *Cell) {
+	defer lockPair(c, neighbor)()
+	c.Link(neighbor)
+}
+
+// SafeUnlink removes the bidirectional link between two cells like Unlink, but
+// may be called from several goroutines at once
+func (c *Cell) SafeUnlink(neighbor *Cell) {
+	defer lockPair(c, neighbor)()
+	c.Unlink(neighbor)
+}
+
+// SafeLinked returns true if a cell is linked to another like Linked, but may be
+// called while other goroutines use SafeLink and SafeUnlink
+func (c *Cell) SafeLinked(neighbor *Cell) bool {
+	i := linkLock(c)
+	linkLocks[i].Lock()
+	defer linkLocks[i].Unlock()
+	return c.Linked(neighbor)
+}
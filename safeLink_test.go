@@ -0,0 +1,55 @@
+package maze
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestSafeLinkConcurrent links and unlinks pairs of cells which share cells
+// from many goroutines at once.  Run it with -race to detect unsynchronized
+// access to the links
+func TestSafeLinkConcurrent(t *testing.T) {
+	g := NewGrid(8, 8)
+	// Every cell not next to the far corner is also linked to it, so the map
+	// of links to cells which aren't neighbors is shared between goroutines
+	far := g.At(7, 7)
+	pairs := [][2]*Cell{}
+	ForEachAdjacentPair(&g, func(a, b *Cell) {
+		pairs = append(pairs, [2]*Cell{a, b})
+	})
+
+	const workers = 8
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				for j, p := range pairs {
+					if (i+j+w)%2 == 0 {
+						p[0].SafeLink(p[1])
+					} else {
+						p[0].SafeUnlink(p[1])
+					}
+					_ = p[1].SafeLinked(p[0])
+					if manhattan(p[0], far) > 1 {
+						p[0].SafeLink(far)
+					}
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	// Whichever goroutine linked or unlinked a pair last, both cells agree
+	for _, p := range pairs {
+		if p[0].Linked(p[1]) != p[1].Linked(p[0]) {
+			t.Errorf("cells [%d, %d] and [%d, %d] disagree about their link", p[0].Row, p[0].Column, p[1].Row, p[1].Column)
+		}
+	}
+	for _, cell := range g.Cells() {
+		if manhattan(cell, far) > 1 && (!cell.Linked(far) || !far.Linked(cell)) {
+			t.Errorf("cell [%d, %d] is not linked to the far corner", cell.Row, cell.Column)
+		}
+	}
+}
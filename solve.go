@@ -0,0 +1,149 @@
+package maze
+
+// ShortestPath returns the cells along a shortest route through linked passages
+// from one cell to another, including both ends.  If the destination can't be
+// reached, it returns nil and false
+func ShortestPath(from, to *Cell) ([]*Cell, bool) {
+	return shortestPath(from, to, (*Cell).Links)
+}
+
+// ShortestPathDiagonal is ShortestPath, but also allows moving diagonally in a
+// single step between cells whose shared corner is open.  A corner is open if
+// either of the L-shaped routes around it is linked, from one cell through an
+// orthogonal neighbor to the other, so each diagonal step cuts a corner which
+// could also be walked in two steps
+func ShortestPathDiagonal(from, to *Cell) ([]*Cell, bool) {
+	return shortestPath(from, to, func(c *Cell) []*Cell {
+		return append(c.Links(), c.diagonalLinks()...)
+	})
+}
+
+// diagonalLinks returns the cells diagonally adjacent to this cell which can be
+// reached through a linked orthogonal neighbor, turning the corner either
+// vertically first or horizontally first
+func (c *Cell) diagonalLinks() []*Cell {
+	ret := []*Cell{}
+	for _, vertical := range []Direction{North, South} {
+		for _, horizontal := range []Direction{East, West} {
+			if v := c.neighbor(vertical); c.Linked(v) && v.Linked(v.neighbor(horizontal)) {
+				ret = append(ret, v.neighbor(horizontal))
+			} else if h := c.neighbor(horizontal); c.Linked(h) && h.Linked(h.neighbor(vertical)) {
+				ret = append(ret, h.neighbor(vertical))
+			}
+		}
+	}
+	return ret
+}
+
+// shortestPath performs a breadth-first search from one cell to another, moving
+// from each cell to the cells next returns
+func shortestPath(from, to *Cell, next func(*Cell) []*Cell) ([]*Cell, bool) {
+	prev := map[*Cell]*Cell{from: nil}
+	frontier := []*Cell{from}
+	for len(frontier) > 0 && frontier[0] != to {
+		cell := frontier[0]
+		frontier = frontier[1:]
+		for _, n := range next(cell) {
+			if _, ok := prev[n]; !ok {
+				prev[n] = cell
+				frontier = append(frontier, n)
+			}
+		}
+	}
+	if _, ok := prev[to]; !ok {
+		return nil, false
+	}
+	return tracePath(prev, to), true
+}
+
+// tracePath follows the predecessors recorded by a search back from a cell to
+// the start of the search, which has a nil predecessor, and returns the cells
+// along the way in order from the start
+func tracePath(prev map[*Cell]*Cell, to *Cell) []*Cell {
+	path := []*Cell{}
+	for cell := to; cell != nil; cell = prev[cell] {
+		path = append(path, cell)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// LongestPath returns the longest shortest route between two cells of a perfect
+// maze, along with its length.  The farthest cell from an arbitrary starting
+// point is one end of the maze's diameter, and the farthest cell from that end
+// is the other
+func LongestPath(g *Grid) ([]*Cell, int64) {
+	first := g.firstCell()
+	if first == nil {
+		return nil, 0
+	}
+	start, _ := ComputeDistances(first).Max()
+	end, length := ComputeDistances(start).Max()
+	path, _ := ShortestPath(start, end)
+	return path, length
+}
+
+// Glyphs used to mark cells by ToStringWithPath
+const (
+	startGlyph = 'S'
+	goalGlyph  = 'G'
+	pathGlyph  = '*'
+)
+
+// ToStringWithPath creates a textual representation of the maze in which the
+// start and goal are marked with S and G and the other cells of the path are
+// marked with *.  Either of start and goal may be nil
+func (g *Grid) ToStringWithPath(path []*Cell, start, goal *Cell) string {
+	onPath := map[*Cell]bool{}
+	for _, cell := range path {
+		onPath[cell] = true
+	}
+	return g.toString(LightWalls, 3, 1, func(cell *Cell) rune {
+		switch {
+		case cell == start:
+			return startGlyph
+		case cell == goal:
+			return goalGlyph
+		case onPath[cell]:
+			return pathGlyph
+		}
+		return ' '
+	})
+}
+
+// wallFollowerLimit bounds the number of steps WallFollower takes, as a multiple
+// of the number of cells it can reach
+const wallFollowerLimit = 4
+
+// WallFollower walks from one cell toward another by keeping its right hand on
+// the wall, beginning while facing the given direction.  At each cell it turns
+// right if it can, otherwise goes straight, otherwise turns left, and otherwise
+// turns back.  It returns every cell along the walk, including both ends and
+// any cells it revisits.  In a perfect maze the walk always reaches the goal,
+// but in a maze with loops it may circle forever, so it gives up and returns
+// false after a number of steps proportional to the size of the maze
+func WallFollower(from, to *Cell, startFacing Direction) ([]*Cell, bool) {
+	limit := wallFollowerLimit * len(Reachable(from))
+	walk := []*Cell{from}
+	cell, facing := from, startFacing
+	for steps := 0; cell != to; steps++ {
+		if steps >= limit {
+			return walk, false
+		}
+		moved := false
+		for _, d := range []Direction{facing.clockwise(), facing, facing.counterClockwise(), facing.opposite()} {
+			if next := cell.neighbor(d); cell.Linked(next) {
+				cell, facing, moved = next, d, true
+				break
+			}
+		}
+		if !moved {
+			// The cell is walled in on every side
+			return walk, false
+		}
+		walk = append(walk, cell)
+	}
+	return walk, true
+}
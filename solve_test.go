@@ -0,0 +1,218 @@
+package maze
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// positions returns the row and column of each cell in a list
+func positions(cells []*Cell) [][2]int64 {
+	ret := [][2]int64{}
+	for _, c := range cells {
+		ret = append(ret, [2]int64{c.Row, c.Column})
+	}
+	return ret
+}
+
+func TestShortestPath(t *testing.T) {
+	square := [][4]int64{{0, 0, 0, 1}, {0, 1, 1, 1}, {1, 1, 1, 0}, {1, 0, 0, 0}}
+	tests := []struct {
+		name     string
+		grid     *Grid
+		from, to [2]int64
+		want     [][2]int64
+		found    bool
+	}{
+		{"SameCell", serpentine(2, 2), [2]int64{0, 0}, [2]int64{0, 0}, [][2]int64{{0, 0}}, true},
+		{"Serpentine", serpentine(3, 2), [2]int64{0, 0}, [2]int64{2, 0}, [][2]int64{{0, 0}, {0, 1}, {1, 1}, {1, 0}, {2, 0}}, true},
+		{"Backwards", serpentine(2, 2), [2]int64{1, 0}, [2]int64{0, 0}, [][2]int64{{1, 0}, {1, 1}, {0, 1}, {0, 0}}, true},
+		{"ShorterWayAroundLoop", linkedGrid(2, 2, square), [2]int64{0, 0}, [2]int64{1, 0}, [][2]int64{{0, 0}, {1, 0}}, true},
+		{"NonNeighborLink", linkedGrid(1, 4, [][4]int64{{0, 0, 0, 1}, {0, 1, 0, 2}, {0, 2, 0, 3}, {0, 0, 0, 3}}), [2]int64{0, 0}, [2]int64{0, 3}, [][2]int64{{0, 0}, {0, 3}}, true},
+		{"Unreachable", linkedGrid(1, 3, [][4]int64{{0, 0, 0, 1}}), [2]int64{0, 0}, [2]int64{0, 2}, nil, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path, ok := ShortestPath(tc.grid.At(tc.from[0], tc.from[1]), tc.grid.At(tc.to[0], tc.to[1]))
+			if ok != tc.found {
+				t.Fatalf("ShortestPath() found = %v, want %v", ok, tc.found)
+			}
+			if !tc.found {
+				if path != nil {
+					t.Errorf("ShortestPath() = %v, want nil", positions(path))
+				}
+				return
+			}
+			if got := positions(path); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ShortestPath() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLongestPath(t *testing.T) {
+	masked := func() *Grid {
+		g := NewMaskedGrid(maskFromString(t, "X..\n..."))
+		g.At(0, 1).Link(g.At(0, 2))
+		g.At(0, 2).Link(g.At(1, 2))
+		g.At(1, 2).Link(g.At(1, 1))
+		g.At(1, 1).Link(g.At(1, 0))
+		return g
+	}
+	tests := []struct {
+		name   string
+		grid   *Grid
+		length int64
+		ends   [2][2]int64
+	}{
+		{"SingleCell", linkedGrid(1, 1, nil), 0, [2][2]int64{{0, 0}, {0, 0}}},
+		{"Serpentine", serpentine(3, 3), 8, [2][2]int64{{0, 0}, {2, 2}}},
+		// A T shape with a stem down from the second cell of the top row, so
+		// the longest route runs from the far end of the top to the stem
+		{"Branches", linkedGrid(3, 4, [][4]int64{{0, 0, 0, 1}, {0, 1, 0, 2}, {0, 2, 0, 3}, {0, 1, 1, 1}, {1, 1, 2, 1}}), 4, [2][2]int64{{0, 3}, {2, 1}}},
+		{"Masked", masked(), 4, [2][2]int64{{0, 1}, {1, 0}}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path, length := LongestPath(tc.grid)
+			if length != tc.length || int64(len(path)) != tc.length+1 {
+				t.Fatalf("LongestPath() = %v, %d, want length %d", positions(path), length, tc.length)
+			}
+			for i := 1; i < len(path); i++ {
+				if !path[i-1].Linked(path[i]) {
+					t.Fatalf("LongestPath() = %v, which crosses a wall", positions(path))
+				}
+			}
+			first, last := positions(path[:1])[0], positions(path[len(path)-1:])[0]
+			if [2][2]int64{first, last} != tc.ends && [2][2]int64{last, first} != tc.ends {
+				t.Errorf("LongestPath() runs from %v to %v, want %v", first, last, tc.ends)
+			}
+		})
+	}
+
+	empty := NewGrid(0, 0)
+	if path, length := LongestPath(&empty); path != nil || length != 0 {
+		t.Errorf("LongestPath() of an empty grid = %v, %d, want nil, 0", positions(path), length)
+	}
+}
+
+func TestToStringWithPath(t *testing.T) {
+	g := serpentine(2, 3)
+	tests := []struct {
+		name        string
+		from, to    [2]int64
+		start, goal *Cell
+		want        string
+	}{
+		{"Marked", [2]int64{0, 0}, [2]int64{1, 0}, g.At(0, 0), g.At(1, 0), "┌───────────┐   \n" +
+			"│ S   *   * │   \n" +
+			"├────────   │   \n" +
+			"│ G   *   * │   \n" +
+			"└───────────┘   \n"},
+		{"Unmarked", [2]int64{0, 1}, [2]int64{1, 1}, nil, nil, "┌───────────┐   \n" +
+			"│     *   * │   \n" +
+			"├────────   │   \n" +
+			"│     *   * │   \n" +
+			"└───────────┘   \n"},
+		// The start and goal are marked even where they aren't on the path
+		{"OffPath", [2]int64{0, 1}, [2]int64{0, 2}, g.At(1, 2), g.At(0, 0), "┌───────────┐   \n" +
+			"│ G   *   * │   \n" +
+			"├────────   │   \n" +
+			"│         S │   \n" +
+			"└───────────┘   \n"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path, _ := ShortestPath(g.At(tc.from[0], tc.from[1]), g.At(tc.to[0], tc.to[1]))
+			if got := g.ToStringWithPath(path, tc.start, tc.goal); got != tc.want {
+				t.Errorf("ToStringWithPath() =\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+	if got, want := g.ToStringWithPath(nil, nil, nil), g.ToString(); got != want {
+		t.Errorf("ToStringWithPath() without a path =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWallFollowerPerfectMaze(t *testing.T) {
+	for seed := int64(0); seed < 5; seed++ {
+		r := rand.New(rand.NewSource(seed))
+		g := NewGrid(12, 12)
+		RecursiveBacktrackerRand(&g, r)
+		for i := 0; i < 20; i++ {
+			from, to := g.RandomCellRand(r), g.RandomCellRand(r)
+			facing := Direction(r.Intn(4))
+			walk, ok := WallFollower(from, to, facing)
+			if !ok || walk[0] != from || walk[len(walk)-1] != to {
+				t.Fatalf("seed %d: WallFollower([%d, %d], [%d, %d], %v) did not reach the goal", seed, from.Row, from.Column, to.Row, to.Column, facing)
+			}
+			for j := 1; j < len(walk); j++ {
+				if !walk[j-1].Linked(walk[j]) {
+					t.Fatalf("seed %d: WallFollower() = %v, which crosses a wall", seed, positions(walk))
+				}
+			}
+			// Following the wall of a tree passes through each passage at most
+			// once in each direction
+			if int64(len(walk)-1) > 2*(g.Size()-1) {
+				t.Errorf("seed %d: WallFollower() took %d steps in a maze of %d cells", seed, len(walk)-1, g.Size())
+			}
+		}
+	}
+}
+
+func TestWallFollowerLoop(t *testing.T) {
+	// A loop around the center cell, which is only reachable from the middle
+	// of the top row
+	g := linkedGrid(3, 3, [][4]int64{{0, 0, 0, 1}, {0, 1, 0, 2}, {0, 2, 1, 2}, {1, 2, 2, 2},
+		{2, 2, 2, 1}, {2, 1, 2, 0}, {2, 0, 1, 0}, {1, 0, 0, 0}, {0, 1, 1, 1}})
+	// Going clockwise around the loop, the opening is on the right hand
+	if walk, ok := WallFollower(g.At(0, 0), g.At(1, 1), North); !ok || len(walk) != 3 {
+		t.Errorf("WallFollower() going clockwise = %v, %v, want the route through the opening", positions(walk), ok)
+	}
+	// Going counterclockwise, the opening is on the left hand, so the walk
+	// circles the loop forever
+	if walk, ok := WallFollower(g.At(0, 2), g.At(1, 1), West); ok {
+		t.Errorf("WallFollower() going counterclockwise = %v, want no route", positions(walk))
+	}
+	// A cell with no passages can't be left at all
+	walled := linkedGrid(1, 2, nil)
+	if walk, ok := WallFollower(walled.At(0, 0), walled.At(0, 1), East); ok || len(walk) != 1 {
+		t.Errorf("WallFollower() from a walled cell = %v, %v, want only the start", positions(walk), ok)
+	}
+}
+
+func TestShortestPathDiagonal(t *testing.T) {
+	tests := []struct {
+		name       string
+		grid       *Grid
+		from, to   [2]int64
+		want       [][2]int64
+		orthogonal int
+	}{
+		// Each turn of the staircase is an open corner
+		{"Staircase", linkedGrid(3, 3, [][4]int64{{0, 0, 0, 1}, {0, 1, 1, 1}, {1, 1, 1, 2}, {1, 2, 2, 2}}),
+			[2]int64{0, 0}, [2]int64{2, 2}, [][2]int64{{0, 0}, {1, 1}, {2, 2}}, 5},
+		{"TurnVerticalFirst", linkedGrid(2, 2, [][4]int64{{0, 0, 1, 0}, {1, 0, 1, 1}}),
+			[2]int64{0, 0}, [2]int64{1, 1}, [][2]int64{{0, 0}, {1, 1}}, 3},
+		{"TurnHorizontalFirst", linkedGrid(2, 2, [][4]int64{{0, 0, 0, 1}, {0, 1, 1, 1}}),
+			[2]int64{0, 0}, [2]int64{1, 1}, [][2]int64{{0, 0}, {1, 1}}, 3},
+		// A wall across each route around the corner between [0, 0] and [1, 1]
+		// leaves it closed, so the path must go around through [0, 2]
+		{"ClosedCorner", linkedGrid(2, 3, [][4]int64{{0, 0, 0, 1}, {0, 1, 0, 2}, {0, 2, 1, 2}, {1, 2, 1, 1}, {1, 1, 1, 0}}),
+			[2]int64{0, 0}, [2]int64{1, 0}, [][2]int64{{0, 0}, {0, 1}, {0, 2}, {1, 1}, {1, 0}}, 6},
+		{"Unreachable", linkedGrid(2, 2, [][4]int64{{0, 0, 0, 1}}),
+			[2]int64{0, 0}, [2]int64{1, 1}, [][2]int64{}, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			from, to := tc.grid.At(tc.from[0], tc.from[1]), tc.grid.At(tc.to[0], tc.to[1])
+			path, ok := ShortestPathDiagonal(from, to)
+			if got := positions(path); ok != (len(tc.want) > 0) || !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ShortestPathDiagonal() = %v, %v, want %v", got, ok, tc.want)
+			}
+			if orthogonal, _ := ShortestPath(from, to); len(orthogonal) != tc.orthogonal {
+				t.Errorf("ShortestPath() has %d cells, want %d", len(orthogonal), tc.orthogonal)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package maze
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ToSVG writes the maze as an SVG document in which every cell is a
+// cellSize x cellSize square.  Each wall segment between two unlinked cells, and
+// each segment of the outer border, is drawn as a separate line.  Horizontal
+// walls are written first, from top to bottom, followed by vertical walls from
+// left to right, so the output is stable for a given maze
+func (g *Grid) ToSVG(w io.Writer, cellSize int) error {
+	if cellSize < 1 {
+		return fmt.Errorf("invalid SVG cell size: %d", cellSize)
+	}
+
+	width, height := int(g.Columns)*cellSize, int(g.Rows)*cellSize
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"-1 -1 %d %d\">\n",
+		width+2, height+2, width+2, height+2)
+	sb.WriteString("<g stroke=\"black\" stroke-width=\"2\" stroke-linecap=\"square\">\n")
+	line := func(x1, y1, x2, y2 int) {
+		fmt.Fprintf(&sb, "<line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\"/>\n", x1, y1, x2, y2)
+	}
+
+	for row := int64(0); row <= g.Rows; row++ {
+		for col := int64(0); col < g.Columns; col++ {
+			if g.horizontalWall(row, col) {
+				x, y := int(col)*cellSize, int(row)*cellSize
+				line(x, y, x+cellSize, y)
+			}
+		}
+	}
+	for col := int64(0); col <= g.Columns; col++ {
+		for row := int64(0); row < g.Rows; row++ {
+			if g.verticalWall(row, col) {
+				x, y := int(col)*cellSize, int(row)*cellSize
+				line(x, y, x, y+cellSize)
+			}
+		}
+	}
+
+	sb.WriteString("</g>\n</svg>\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
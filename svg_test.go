@@ -0,0 +1,67 @@
+package maze
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestToSVG(t *testing.T) {
+	tests := []struct {
+		name  string
+		grid  *Grid
+		lines int
+	}{
+		{"Empty", linkedGrid(0, 0, nil), 0},
+		{"Unlinked", linkedGrid(2, 2, nil), 12},
+		{"FullyLinked", func() *Grid { g := NewFullyLinkedGrid(2, 2); return &g }(), 8},
+		{"Serpentine", serpentine(2, 2), 9},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := tc.grid.ToSVG(&out, 10); err != nil {
+				t.Fatal(err)
+			}
+			if n := strings.Count(out.String(), "<line "); n != tc.lines {
+				t.Errorf("ToSVG() drew %d lines, want %d", n, tc.lines)
+			}
+			// The document is well formed
+			d := xml.NewDecoder(&out)
+			for {
+				if _, err := d.Token(); err != nil {
+					if err != io.EOF {
+						t.Errorf("ToSVG() is not valid XML: %v", err)
+					}
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestToSVGGolden(t *testing.T) {
+	g := NewGrid(1, 2)
+	var out bytes.Buffer
+	if err := g.ToSVG(&out, 10); err != nil {
+		t.Fatal(err)
+	}
+	want := "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"22\" height=\"12\" viewBox=\"-1 -1 22 12\">\n" +
+		"<g stroke=\"black\" stroke-width=\"2\" stroke-linecap=\"square\">\n" +
+		"<line x1=\"0\" y1=\"0\" x2=\"10\" y2=\"0\"/>\n" +
+		"<line x1=\"10\" y1=\"0\" x2=\"20\" y2=\"0\"/>\n" +
+		"<line x1=\"0\" y1=\"10\" x2=\"10\" y2=\"10\"/>\n" +
+		"<line x1=\"10\" y1=\"10\" x2=\"20\" y2=\"10\"/>\n" +
+		"<line x1=\"0\" y1=\"0\" x2=\"0\" y2=\"10\"/>\n" +
+		"<line x1=\"10\" y1=\"0\" x2=\"10\" y2=\"10\"/>\n" +
+		"<line x1=\"20\" y1=\"0\" x2=\"20\" y2=\"10\"/>\n" +
+		"</g>\n</svg>\n"
+	if out.String() != want {
+		t.Errorf("ToSVG() =\n%s\nwant:\n%s", out.String(), want)
+	}
+	if err := g.ToSVG(&out, 0); err == nil {
+		t.Error("ToSVG() with a cell size of 0 returned no error")
+	}
+}
@@ -0,0 +1,65 @@
+package maze
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// RotationalSymmetricMaze creates a maze which looks the same after being
+// rotated by 90 degrees.  A maze is carved in one quadrant of the square grid
+// and copied into the other three under rotation.  Any existing links in the
+// grid are removed first.
+//
+// The quadrants are arranged in a pinwheel around the center cell, which is
+// linked into each of them, so the result is a perfect maze.  A grid of even
+// size has no center cell, and any connected maze on it which is unchanged by a
+// quarter turn must contain a loop, so even sizes are rejected
+func RotationalSymmetricMaze(g *Grid, r *rand.Rand) error {
+	if g.Rows != g.Columns {
+		return fmt.Errorf("rotational symmetry requires a square grid, not [%d, %d]", g.Rows, g.Columns)
+	}
+	if g.mask != nil {
+		return fmt.Errorf("rotational symmetry is not supported on masked grids")
+	}
+	if g.Rows > 0 && g.Rows%2 == 0 {
+		return fmt.Errorf("a perfect maze with rotational symmetry requires an odd size, not %d", g.Rows)
+	}
+	g.Reset()
+	n := g.Rows
+	half := n / 2
+	if half == 0 {
+		return nil
+	}
+
+	// The first quadrant is the top-left block, widened by one column to reach
+	// the center column
+	inQuadrant := func(c *Cell) bool {
+		return c.Row < half && c.Column < n-half
+	}
+	rotate := func(c *Cell) *Cell {
+		return g.At(c.Column, n-1-c.Row)
+	}
+	linkRotations := func(a, b *Cell) {
+		for i := 0; i < 4; i++ {
+			a.Link(b)
+			a, b = rotate(a), rotate(b)
+		}
+	}
+
+	recursiveBacktracker(g, g.At(0, 0), r, func(from, to *Cell) bool {
+		return inQuadrant(to)
+	})
+	quadrantLinks := [][2]*Cell{}
+	ForEachAdjacentPair(g, func(a, b *Cell) {
+		if inQuadrant(a) && inQuadrant(b) && a.Linked(b) {
+			quadrantLinks = append(quadrantLinks, [2]*Cell{a, b})
+		}
+	})
+	for _, link := range quadrantLinks {
+		linkRotations(link[0], link[1])
+	}
+
+	center := g.At(half, half)
+	linkRotations(center, center.North)
+	return nil
+}
@@ -0,0 +1,44 @@
+package maze
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestRotationalSymmetricMaze(t *testing.T) {
+	for _, n := range []int64{1, 3, 5, 9} {
+		for seed := int64(0); seed < 5; seed++ {
+			g := NewGrid(n, n)
+			if err := RotationalSymmetricMaze(&g, rand.New(rand.NewSource(seed))); err != nil {
+				t.Fatalf("size %d: %v", n, err)
+			}
+			if !IsPerfect(&g) || len(components(&g)) != 1 {
+				t.Errorf("size %d seed %d: maze is not a connected tree:\n%s", n, seed, g.ToString())
+			}
+			rotate := func(c *Cell) *Cell { return g.At(c.Column, n-1-c.Row) }
+			ForEachAdjacentPair(&g, func(a, b *Cell) {
+				if a.Linked(b) != rotate(a).Linked(rotate(b)) {
+					t.Errorf("size %d seed %d: link [%d, %d]-[%d, %d] is not preserved by rotation", n, seed, a.Row, a.Column, b.Row, b.Column)
+				}
+			})
+		}
+	}
+}
+
+func TestRotationalSymmetricMazeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		grid *Grid
+	}{
+		{"EvenSize", serpentine(4, 4)},
+		{"NotSquare", serpentine(3, 5)},
+		{"Masked", NewMaskedGrid(maskFromString(t, "...\n.X.\n..."))},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := RotationalSymmetricMaze(tc.grid, rand.New(rand.NewSource(1))); err == nil {
+				t.Error("RotationalSymmetricMaze() succeeded, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package maze
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"math/rand"
+)
+
+// TriangleCell represents a triangular cell in a maze.  Cells alternate between
+// pointing up and pointing down, so each has neighbors to the east and west and
+// one more either above or below it
+type TriangleCell struct {
+	// The location of this cell in the grid
+	Row, Column int64
+	// The neighbors of this cell.  Upright cells have no North neighbor and
+	// inverted cells have no South neighbor
+	North, South, East, West *TriangleCell
+	// The cells directly linked to this cell
+	links nodeLinks
+}
+
+var _ Node = (*TriangleCell)(nil)
+var _ Graph = (*TriangleGrid)(nil)
+
+// Upright returns true if the cell points up, with its flat side at the bottom
+func (c *TriangleCell) Upright() bool {
+	return (c.Row+c.Column)%2 == 0
+}
+
+// Link links one cell to another bidirectionally
+func (c *TriangleCell) Link(neighbor *TriangleCell) {
+	c.links.add(neighbor)
+	neighbor.links.add(c)
+}
+
+// Unlink removes the bidirectional link between two cells
+func (c *TriangleCell) Unlink(neighbor *TriangleCell) {
+	c.links.remove(neighbor)
+	neighbor.links.remove(c)
+}
+
+// Linked returns true if a cell is linked to another
+func (c *TriangleCell) Linked(neighbor *TriangleCell) bool {
+	return neighbor != nil && c.links.has(neighbor)
+}
+
+// LinkNode links this cell to another bidirectionally.  The other cell must be a
+// *TriangleCell
+func (c *TriangleCell) LinkNode(neighbor Node) {
+	c.Link(neighbor.(*TriangleCell))
+}
+
+// LinkedNodes returns the cells this cell is linked to, in the order the links
+// were made
+func (c *TriangleCell) LinkedNodes() []Node {
+	return c.links.nodes()
+}
+
+// NeighborNodes returns the direct neighbors of this cell, in the same order as
+// Neighbors
+func (c *TriangleCell) NeighborNodes() []Node {
+	ret := []Node{}
+	for _, n := range c.Neighbors() {
+		ret = append(ret, n)
+	}
+	return ret
+}
+
+// Neighbors returns the list of direct neighbors of this cell
+func (c *TriangleCell) Neighbors() []*TriangleCell {
+	ret := []*TriangleCell{}
+	for _, n := range []*TriangleCell{c.North, c.South, c.East, c.West} {
+		if n != nil {
+			ret = append(ret, n)
+		}
+	}
+	return ret
+}
+
+// TriangleGrid represents a maze of triangular cells
+type TriangleGrid struct {
+	// Rows and Columns indicate the size of the grid
+	Rows, Columns int64
+	// The cells in the grid
+	grid [][]*TriangleCell
+}
+
+// NewTriangleGrid creates a new triangular grid.  The upper-left cell points up.
+// An error is returned if either dimension is negative
+func NewTriangleGrid(rows, columns int64) (*TriangleGrid, error) {
+	if rows < 0 || columns < 0 {
+		return nil, fmt.Errorf("triangle grid dimensions invalid: [%d, %d]", rows, columns)
+	}
+	g := &TriangleGrid{
+		Rows:    rows,
+		Columns: columns,
+		grid:    make([][]*TriangleCell, rows)}
+	g.prepareGrid()
+	g.configureCells()
+	return g, nil
+}
+
+// prepareGrid creates the cells in the grid
+func (g *TriangleGrid) prepareGrid() {
+	for r := int64(0); r < g.Rows; r++ {
+		g.grid[r] = make([]*TriangleCell, g.Columns)
+		for c := int64(0); c < g.Columns; c++ {
+			g.grid[r][c] = &TriangleCell{Row: r, Column: c}
+		}
+	}
+}
+
+// configureCells establishes the neighbors of each cell
+func (g *TriangleGrid) configureCells() {
+	for _, cell := range g.Cells() {
+		cell.West = g.At(cell.Row, cell.Column-1)
+		cell.East = g.At(cell.Row, cell.Column+1)
+		if cell.Upright() {
+			cell.South = g.At(cell.Row+1, cell.Column)
+		} else {
+			cell.North = g.At(cell.Row-1, cell.Column)
+		}
+	}
+}
+
+// At accesses a cell from the grid
+func (g *TriangleGrid) At(row, column int64) *TriangleCell {
+	if row < 0 || column < 0 || row >= g.Rows || column >= g.Columns {
+		return nil
+	}
+	return g.grid[row][column]
+}
+
+// AllCells iterates over all of the cells in the grid
+func (g *TriangleGrid) AllCells() <-chan *TriangleCell {
+	c := make(chan *TriangleCell)
+	go func() {
+		for _, cell := range g.Cells() {
+			c <- cell
+		}
+		close(c)
+	}()
+	return c
+}
+
+// Cells returns all of the cells in the grid in row-major order
+func (g *TriangleGrid) Cells() []*TriangleCell {
+	cells := make([]*TriangleCell, 0, g.Size())
+	for _, row := range g.grid {
+		cells = append(cells, row...)
+	}
+	return cells
+}
+
+// Nodes returns all of the cells in the grid in row-major order
+func (g *TriangleGrid) Nodes() []Node {
+	ret := []Node{}
+	for _, cell := range g.Cells() {
+		ret = append(ret, cell)
+	}
+	return ret
+}
+
+// RandomCell returns a random cell from the grid
+func (g *TriangleGrid) RandomCell() *TriangleCell {
+	return g.RandomCellRand(defaultRand)
+}
+
+// RandomCellRand returns a random cell from the grid chosen using the provided
+// random source.  If the grid has no cells, it returns nil
+func (g *TriangleGrid) RandomCellRand(r *rand.Rand) *TriangleCell {
+	if g.Size() == 0 {
+		return nil
+	}
+	return g.At(r.Int63n(g.Rows), r.Int63n(g.Columns))
+}
+
+// RandomNode returns a random cell from the grid chosen using the provided
+// random source, or nil if the grid has no cells
+func (g *TriangleGrid) RandomNode(r *rand.Rand) Node {
+	if c := g.RandomCellRand(r); c != nil {
+		return c
+	}
+	return nil
+}
+
+// Size returns the number of cells in the grid
+func (g *TriangleGrid) Size() int64 {
+	return g.Rows * g.Columns
+}
+
+// ToPNG renders the maze as a PNG image.  cellSize is the length in pixels of
+// each side of a triangle
+func (g *TriangleGrid) ToPNG(w io.Writer, cellSize int) error {
+	if cellSize < 1 {
+		return fmt.Errorf("invalid PNG cell size: %d", cellSize)
+	}
+
+	halfWidth := float64(cellSize) / 2
+	height := float64(cellSize) * math.Sqrt(3) / 2
+	imgWidth := int(math.Ceil(halfWidth*float64(g.Columns+1))) + 1
+	imgHeight := int(math.Ceil(height*float64(g.Rows))) + 1
+	img := image.NewRGBA(image.Rect(0, 0, imgWidth, imgHeight))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: background}, image.Point{}, draw.Src)
+
+	line := func(x0, y0, x1, y1 float64) {
+		drawLine(img, int(math.Round(x0)), int(math.Round(y0)), int(math.Round(x1)), int(math.Round(y1)), wallColor)
+	}
+
+	for _, cell := range g.Cells() {
+		cx := halfWidth * float64(cell.Column+1)
+		westX, eastX := cx-halfWidth, cx+halfWidth
+		top, bottom := height*float64(cell.Row), height*float64(cell.Row+1)
+
+		// The point of the triangle and the y coordinate of its flat side
+		tipY, baseY := top, bottom
+		if !cell.Upright() {
+			tipY, baseY = bottom, top
+		}
+
+		if !cell.Linked(cell.West) {
+			line(westX, baseY, cx, tipY)
+		}
+		if !cell.Linked(cell.East) {
+			line(eastX, baseY, cx, tipY)
+		}
+		if (cell.Upright() && !cell.Linked(cell.South)) || (!cell.Upright() && !cell.Linked(cell.North)) {
+			line(westX, baseY, eastX, baseY)
+		}
+	}
+
+	return png.Encode(w, img)
+}
@@ -0,0 +1,133 @@
+package maze
+
+import (
+	"bytes"
+	"image/png"
+	"math/rand"
+	"testing"
+)
+
+func TestTriangleNeighbors(t *testing.T) {
+	g, err := NewTriangleGrid(8, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	none := [2]int64{-1, -1}
+	tests := []struct {
+		name                     string
+		cell                     [2]int64
+		upright                  bool
+		north, south, east, west [2]int64
+	}{
+		{"Upright", [2]int64{2, 4}, true, none, [2]int64{3, 4}, [2]int64{2, 5}, [2]int64{2, 3}},
+		{"Inverted", [2]int64{2, 5}, false, [2]int64{1, 5}, none, [2]int64{2, 6}, [2]int64{2, 4}},
+		{"OddRowUpright", [2]int64{3, 3}, true, none, [2]int64{4, 3}, [2]int64{3, 4}, [2]int64{3, 2}},
+		{"OddRowInverted", [2]int64{3, 4}, false, [2]int64{2, 4}, none, [2]int64{3, 5}, [2]int64{3, 3}},
+		{"Corner", [2]int64{0, 0}, true, none, [2]int64{1, 0}, [2]int64{0, 1}, none},
+		{"TopEdgeInverted", [2]int64{0, 1}, false, none, none, [2]int64{0, 2}, [2]int64{0, 0}},
+		{"BottomEdgeUpright", [2]int64{7, 1}, true, none, none, [2]int64{7, 2}, [2]int64{7, 0}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := g.At(tc.cell[0], tc.cell[1])
+			if c.Upright() != tc.upright {
+				t.Errorf("Upright() = %v, want %v", c.Upright(), tc.upright)
+			}
+			got := []*TriangleCell{c.North, c.South, c.East, c.West}
+			for i, want := range [][2]int64{tc.north, tc.south, tc.east, tc.west} {
+				if got[i] != g.At(want[0], want[1]) {
+					t.Errorf("neighbor %d = %v, want %v", i, got[i], want)
+				}
+			}
+		})
+	}
+
+	for _, cell := range g.Cells() {
+		interior := cell.Row > 0 && cell.Row < g.Rows-1 && cell.Column > 0 && cell.Column < g.Columns-1
+		if interior && len(cell.Neighbors()) != 3 {
+			t.Errorf("interior cell [%d, %d] has %d neighbors", cell.Row, cell.Column, len(cell.Neighbors()))
+		}
+		for _, n := range cell.Neighbors() {
+			if !containsTriangleCell(n.Neighbors(), cell) {
+				t.Errorf("cell [%d, %d] isn't a neighbor of its neighbor [%d, %d]", cell.Row, cell.Column, n.Row, n.Column)
+			}
+		}
+	}
+}
+
+// containsTriangleCell returns true if a cell appears in a list of cells
+func containsTriangleCell(cells []*TriangleCell, c *TriangleCell) bool {
+	for _, x := range cells {
+		if x == c {
+			return true
+		}
+	}
+	return false
+}
+
+func TestNewTriangleGrid(t *testing.T) {
+	tests := []struct {
+		rows, columns int64
+		valid         bool
+	}{
+		{0, 0, true},
+		{3, 4, true},
+		{-1, 4, false},
+		{3, -1, false},
+	}
+	for _, tc := range tests {
+		g, err := NewTriangleGrid(tc.rows, tc.columns)
+		if (err == nil) != tc.valid {
+			t.Errorf("NewTriangleGrid(%d, %d) returned %v", tc.rows, tc.columns, err)
+			continue
+		}
+		if tc.valid && (g.Size() != tc.rows*tc.columns || int64(len(g.Nodes())) != g.Size()) {
+			t.Errorf("NewTriangleGrid(%d, %d) has %d cells", tc.rows, tc.columns, g.Size())
+		}
+	}
+}
+
+func TestTriangleMaze(t *testing.T) {
+	for seed := int64(0); seed < 5; seed++ {
+		g, err := NewTriangleGrid(8, 16)
+		if err != nil {
+			t.Fatal(err)
+		}
+		RecursiveBacktrackerGraph(g, rand.New(rand.NewSource(seed)))
+		if !graphIsPerfect(g) {
+			t.Fatalf("seed %d: maze is not perfect", seed)
+		}
+		for _, cell := range g.Cells() {
+			for _, n := range cell.LinkedNodes() {
+				if !n.(*TriangleCell).Linked(cell) {
+					t.Fatalf("seed %d: cell [%d, %d] is linked one way", seed, cell.Row, cell.Column)
+				}
+			}
+		}
+
+		var out bytes.Buffer
+		if err := g.ToPNG(&out, 10); err != nil {
+			t.Fatal(err)
+		}
+		img, err := png.Decode(&out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if size := img.Bounds().Size(); size.X != 86 || size.Y != 71 {
+			t.Errorf("image is %v, want 86x71", size)
+		}
+	}
+}
+
+func TestTriangleRandomCell(t *testing.T) {
+	empty, err := NewTriangleGrid(2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := empty.RandomCellRand(rand.New(rand.NewSource(1))); c != nil {
+		t.Errorf("RandomCellRand() of an empty grid = [%d, %d], want nil", c.Row, c.Column)
+	}
+	if n := empty.RandomNode(rand.New(rand.NewSource(1))); n != nil {
+		t.Errorf("RandomNode() of an empty grid = %v, want nil", n)
+	}
+}
@@ -0,0 +1,55 @@
+package maze
+
+// unionFind tracks which cells belong to the same set, allowing sets to be
+// merged efficiently
+type unionFind struct {
+	// The parent of each cell in its set's tree.  Roots are their own parents
+	parent map[*Cell]*Cell
+	// The number of cells in the set rooted at each root
+	size map[*Cell]int
+	// The number of distinct sets
+	count int
+}
+
+// newUnionFind creates a union-find structure with each cell in its own set
+func newUnionFind(cells []*Cell) *unionFind {
+	u := &unionFind{
+		parent: make(map[*Cell]*Cell, len(cells)),
+		size:   make(map[*Cell]int, len(cells))}
+	for _, c := range cells {
+		u.parent[c] = c
+		u.size[c] = 1
+	}
+	u.count = len(cells)
+	return u
+}
+
+// find returns the root of the set containing a cell
+func (u *unionFind) find(c *Cell) *Cell {
+	root := c
+	for u.parent[root] != root {
+		root = u.parent[root]
+	}
+	// Point everything along the way directly at the root
+	for c != root {
+		c, u.parent[c] = u.parent[c], root
+	}
+	return root
+}
+
+// union merges the sets containing two cells.  It returns false if they were
+// already in the same set
+func (u *unionFind) union(a, b *Cell) bool {
+	ra, rb := u.find(a), u.find(b)
+	if ra == rb {
+		return false
+	}
+	if u.size[ra] < u.size[rb] {
+		ra, rb = rb, ra
+	}
+	u.parent[rb] = ra
+	u.size[ra] += u.size[rb]
+	delete(u.size, rb)
+	u.count--
+	return true
+}
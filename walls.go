@@ -0,0 +1,159 @@
+package maze
+
+import (
+	"fmt"
+)
+
+// opening identifies a side of a cell on the border of the grid which has been
+// opened
+type opening struct {
+	cell *Cell
+	dir  Direction
+}
+
+// OpenBorder removes the border wall on one side of a cell, such as to make an
+// entrance or exit.  An error is returned if the cell has a neighbor on that
+// side or is not part of the grid
+func (g *Grid) OpenBorder(c *Cell, dir Direction) error {
+	if c == nil || g.At(c.Row, c.Column) != c {
+		return fmt.Errorf("cell is not part of the grid")
+	}
+	if dir < North || dir > West {
+		return fmt.Errorf("unknown direction %v", dir)
+	}
+	if c.neighbor(dir) != nil {
+		return fmt.Errorf("cell [%d, %d] is not on the %v border", c.Row, c.Column, dir)
+	}
+	if g.openings == nil {
+		g.openings = map[opening]bool{}
+	}
+	g.openings[opening{c, dir}] = true
+	return nil
+}
+
+// closed returns true if there is no passage out of a cell in the given
+// direction
+func (g *Grid) closed(c *Cell, dir Direction) bool {
+	return !c.Linked(c.neighbor(dir)) && !g.openings[opening{c, dir}]
+}
+
+// horizontalWall returns true if a wall stands along the top edge of the cell at
+// the given position.  The row may be equal to the number of rows in the grid to
+// inspect the bottom border
+func (g *Grid) horizontalWall(row, column int64) bool {
+	above := g.At(row-1, column)
+	below := g.At(row, column)
+	// A passage beneath a weave leaves the cell above it through only one of
+	// the cells on either side of the edge, so either one can open it
+	if above != nil && !g.closed(above, South) {
+		return false
+	}
+	if below != nil && !g.closed(below, North) {
+		return false
+	}
+	return above != nil || below != nil
+}
+
+// verticalWall returns true if a wall stands along the left edge of the cell at
+// the given position.  The column may be equal to the number of columns in the
+// grid to inspect the right border
+func (g *Grid) verticalWall(row, column int64) bool {
+	left := g.At(row, column-1)
+	right := g.At(row, column)
+	if left != nil && !g.closed(left, East) {
+		return false
+	}
+	if right != nil && !g.closed(right, West) {
+		return false
+	}
+	return left != nil || right != nil
+}
+
+// WallSegments returns every standing wall in the maze, including the outer
+// border, as [x1, y1, x2, y2] grid-line coordinates where x counts columns and y
+// counts rows.  Walls which continue in a straight line are merged into a single
+// segment.  Horizontal segments are listed first, from top to bottom, followed
+// by vertical segments from left to right
+func (g *Grid) WallSegments() [][4]int {
+	segments := [][4]int{}
+	for row := int64(0); row <= g.Rows; row++ {
+		start := int64(-1)
+		for col := int64(0); col <= g.Columns; col++ {
+			if col < g.Columns && g.horizontalWall(row, col) {
+				if start < 0 {
+					start = col
+				}
+			} else if start >= 0 {
+				segments = append(segments, [4]int{int(start), int(row), int(col), int(row)})
+				start = -1
+			}
+		}
+	}
+	for col := int64(0); col <= g.Columns; col++ {
+		start := int64(-1)
+		for row := int64(0); row <= g.Rows; row++ {
+			if row < g.Rows && g.verticalWall(row, col) {
+				if start < 0 {
+					start = row
+				}
+			} else if start >= 0 {
+				segments = append(segments, [4]int{int(col), int(start), int(col), int(row)})
+				start = -1
+			}
+		}
+	}
+	return segments
+}
+
+// wallCount returns the number of cell-length wall segments standing in the
+// maze, including the outer border
+func (g *Grid) wallCount() int64 {
+	count := int64(0)
+	for row := int64(0); row <= g.Rows; row++ {
+		for col := int64(0); col <= g.Columns; col++ {
+			if col < g.Columns && g.horizontalWall(row, col) {
+				count++
+			}
+			if row < g.Rows && g.verticalWall(row, col) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// ToWallBitmap returns the maze as a (2 * Rows + 1) x (2 * Columns + 1) grid of
+// tiles in which true marks a wall, as used by tile-based game engines.  The
+// tile at [2*r+1][2*c+1] is the center of the cell at [r, c], the tiles between
+// two centers are the edges between those cells, and the tiles at even indices
+// in both dimensions are the posts at their corners, which are always walls.
+// Cells disabled by a mask are solid
+func (g *Grid) ToWallBitmap() [][]bool {
+	bitmap := make([][]bool, 2*g.Rows+1)
+	for y := range bitmap {
+		bitmap[y] = make([]bool, 2*g.Columns+1)
+		for x := range bitmap[y] {
+			bitmap[y][x] = y%2 == 0 && x%2 == 0
+		}
+	}
+	// solid returns true if there is no enabled cell on either side of an edge
+	solid := func(a, b *Cell) bool {
+		return a == nil && b == nil
+	}
+	for row := int64(0); row <= g.Rows; row++ {
+		for col := int64(0); col <= g.Columns; col++ {
+			if row < g.Rows && col < g.Columns {
+				bitmap[2*row+1][2*col+1] = g.At(row, col) == nil
+			}
+			if col < g.Columns {
+				bitmap[2*row][2*col+1] = g.horizontalWall(row, col) ||
+					solid(g.At(row-1, col), g.At(row, col))
+			}
+			if row < g.Rows {
+				bitmap[2*row+1][2*col] = g.verticalWall(row, col) ||
+					solid(g.At(row, col-1), g.At(row, col))
+			}
+		}
+	}
+	return bitmap
+}
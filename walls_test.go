@@ -0,0 +1,149 @@
+package maze
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestWallSegments(t *testing.T) {
+	opened := NewGrid(1, 2)
+	opened.OpenBorder(opened.At(0, 0), North)
+	tests := []struct {
+		name string
+		grid *Grid
+		want [][4]int
+	}{
+		{"Empty", linkedGrid(0, 0, nil), [][4]int{}},
+		{"SingleCell", linkedGrid(1, 1, nil), [][4]int{{0, 0, 1, 0}, {0, 1, 1, 1}, {0, 0, 0, 1}, {1, 0, 1, 1}}},
+		{"Unlinked", linkedGrid(2, 2, nil), [][4]int{
+			{0, 0, 2, 0}, {0, 1, 2, 1}, {0, 2, 2, 2},
+			{0, 0, 0, 2}, {1, 0, 1, 2}, {2, 0, 2, 2}}},
+		{"UShape", linkedGrid(2, 2, [][4]int64{{0, 0, 1, 0}, {1, 0, 1, 1}, {1, 1, 0, 1}}), [][4]int{
+			{0, 0, 2, 0}, {0, 2, 2, 2},
+			{0, 0, 0, 2}, {1, 0, 1, 1}, {2, 0, 2, 2}}},
+		{"OpenBorder", &opened, [][4]int{{1, 0, 2, 0}, {0, 1, 2, 1}, {0, 0, 0, 1}, {1, 0, 1, 1}, {2, 0, 2, 1}}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.grid.WallSegments(); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("WallSegments() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestOpenBorder(t *testing.T) {
+	g := NewGrid(5, 6)
+	RecursiveBacktrackerRand(&g, rand.New(rand.NewSource(1)))
+	if err := g.OpenBorder(g.At(0, 0), West); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.OpenBorder(g.At(4, 5), South); err != nil {
+		t.Fatal(err)
+	}
+	// Opening the same side again changes nothing
+	if err := g.OpenBorder(g.At(0, 0), West); err != nil {
+		t.Fatal(err)
+	}
+
+	gaps := [][3]int64{}
+	for c := int64(0); c < g.Columns; c++ {
+		if !g.horizontalWall(0, c) {
+			gaps = append(gaps, [3]int64{0, c, int64(North)})
+		}
+		if !g.horizontalWall(g.Rows, c) {
+			gaps = append(gaps, [3]int64{g.Rows - 1, c, int64(South)})
+		}
+	}
+	for r := int64(0); r < g.Rows; r++ {
+		if !g.verticalWall(r, 0) {
+			gaps = append(gaps, [3]int64{r, 0, int64(West)})
+		}
+		if !g.verticalWall(r, g.Columns) {
+			gaps = append(gaps, [3]int64{r, g.Columns - 1, int64(East)})
+		}
+	}
+	want := [][3]int64{{4, 5, int64(South)}, {0, 0, int64(West)}}
+	if !reflect.DeepEqual(gaps, want) {
+		t.Errorf("border has gaps at %v, want %v", gaps, want)
+	}
+
+	small := serpentine(2, 2)
+	small.OpenBorder(small.At(0, 0), North)
+	small.OpenBorder(small.At(1, 0), West)
+	wantString := "│   ────┐   \n" +
+		"│       │   \n" +
+		"└────   │   \n" +
+		"        │   \n" +
+		"────────┘   \n"
+	if got := small.ToString(); got != wantString {
+		t.Errorf("ToString() =\n%s\nwant:\n%s", got, wantString)
+	}
+}
+
+func TestOpenBorderErrors(t *testing.T) {
+	g := NewGrid(3, 3)
+	other := NewGrid(3, 3)
+	tests := []struct {
+		name string
+		cell *Cell
+		dir  Direction
+	}{
+		{"Interior", g.At(1, 1), North},
+		{"InwardFromBorder", g.At(0, 1), South},
+		{"NilCell", nil, North},
+		{"OtherGrid", other.At(0, 0), North},
+		{"UnknownDirection", g.At(0, 0), Direction(9)},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := g.OpenBorder(tc.cell, tc.dir); err == nil {
+				t.Error("OpenBorder() succeeded, want an error")
+			}
+			if len(g.openings) != 0 {
+				t.Errorf("OpenBorder() recorded %d openings", len(g.openings))
+			}
+		})
+	}
+}
+
+func TestToWallBitmap(t *testing.T) {
+	masked := NewMaskedGrid(maskFromString(t, ".X\n.."))
+	masked.At(0, 0).Link(masked.At(1, 0))
+	opened := serpentine(2, 2)
+	opened.OpenBorder(opened.At(0, 0), North)
+	opened.OpenBorder(opened.At(1, 0), West)
+	cylinder := NewCylinderGrid(1, 3)
+	cylinder.At(0, 0).Link(cylinder.At(0, 2))
+	tests := []struct {
+		name string
+		grid *Grid
+		want []string
+	}{
+		{"Serpentine", serpentine(2, 2), []string{"#####", "#...#", "###.#", "#...#", "#####"}},
+		{"Masked", masked, []string{"#####", "#.###", "#.###", "#.#.#", "#####"}},
+		{"Openings", opened, []string{"#.###", "#...#", "###.#", "....#", "#####"}},
+		{"CylinderWrap", &cylinder, []string{"#######", "..#.#..", "#######"}},
+		{"Empty", linkedGrid(0, 0, nil), []string{"#"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := []string{}
+			for _, row := range tc.grid.ToWallBitmap() {
+				line := ""
+				for _, wall := range row {
+					if wall {
+						line += "#"
+					} else {
+						line += "."
+					}
+				}
+				got = append(got, line)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ToWallBitmap() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
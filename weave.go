@@ -0,0 +1,345 @@
+package maze
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+	"math/rand"
+)
+
+// WeaveGrid represents a rectangular maze in which passages may run beneath
+// the cells of the grid.  The cells of the grid are the over cells.  A passage
+// which tunnels beneath an over cell passes through an under cell, which shares
+// the position of the over cell and takes its place as the neighbor of the
+// cells on either side of the tunnel
+type WeaveGrid struct {
+	Grid
+	// The cells of the passages which run beneath the grid
+	under []*Cell
+}
+
+// NewWeaveGrid creates a new rectangular grid in which passages may be woven
+// over and under each other
+func NewWeaveGrid(rows, columns int64) *WeaveGrid {
+	return &WeaveGrid{Grid: NewGrid(rows, columns)}
+}
+
+// UnderCells returns the cells of the passages which run beneath the grid, in
+// the order they were created
+func (g *WeaveGrid) UnderCells() []*Cell {
+	return append([]*Cell(nil), g.under...)
+}
+
+// Reset removes every link between cells in the grid along with every passage
+// running beneath it
+func (g *WeaveGrid) Reset() {
+	for _, under := range g.under {
+		over := g.At(under.Row, under.Column)
+		if under.North != nil {
+			under.North.South, under.South.North = over, over
+		} else {
+			under.West.East, under.East.West = over, over
+		}
+	}
+	g.under = nil
+	g.Grid.Reset()
+}
+
+// isOverCell returns true if a cell belongs to the grid rather than running
+// beneath it
+func (g *WeaveGrid) isOverCell(c *Cell) bool {
+	return c != nil && g.At(c.Row, c.Column) == c
+}
+
+// horizontalPassage returns true if a cell is part of a corridor running
+// straight from east to west
+func horizontalPassage(c *Cell) bool {
+	return c.Linked(c.East) && c.Linked(c.West) && !c.Linked(c.North) && !c.Linked(c.South)
+}
+
+// verticalPassage returns true if a cell is part of a corridor running straight
+// from north to south
+func verticalPassage(c *Cell) bool {
+	return c.Linked(c.North) && c.Linked(c.South) && !c.Linked(c.East) && !c.Linked(c.West)
+}
+
+// canTunnel returns true if a passage could run from a cell beneath its
+// neighbor in the given direction
+func (g *WeaveGrid) canTunnel(c *Cell, d Direction) bool {
+	over := c.neighbor(d)
+	if !g.isOverCell(over) || over.neighbor(d) == nil {
+		return false
+	}
+	if d == North || d == South {
+		return horizontalPassage(over)
+	}
+	return verticalPassage(over)
+}
+
+// weaveNeighbors returns the neighbors of a cell along with the cells which can
+// be reached by tunneling beneath a neighbor
+func (g *WeaveGrid) weaveNeighbors(c *Cell) []*Cell {
+	ret := c.Neighbors()
+	for _, d := range []Direction{North, South, East, West} {
+		if g.canTunnel(c, d) {
+			ret = append(ret, c.neighbor(d).neighbor(d))
+		}
+	}
+	return ret
+}
+
+// Tunnel links two cells which lie two apart in a straight line with a passage
+// running beneath the cell between them.  The cell between them must be part of
+// a straight corridor running perpendicular to the tunnel
+func (g *WeaveGrid) Tunnel(from, to *Cell) error {
+	for _, d := range []Direction{North, South, East, West} {
+		over := from.neighbor(d)
+		if over == nil || over.neighbor(d) != to {
+			continue
+		}
+		if !g.canTunnel(from, d) {
+			return fmt.Errorf("cannot tunnel beneath cell [%d, %d]", over.Row, over.Column)
+		}
+
+		under := g.addUnder(over, d == North || d == South)
+		for _, n := range under.Neighbors() {
+			under.Link(n)
+		}
+		return nil
+	}
+	return fmt.Errorf("cells [%d, %d] and [%d, %d] are not two apart in a straight line",
+		from.Row, from.Column, to.Row, to.Column)
+}
+
+// addUnder creates an unlinked cell beneath an over cell, running north to south
+// if vertical is true and east to west otherwise, and makes it the neighbor of
+// the cells on either side in place of the over cell
+func (g *WeaveGrid) addUnder(over *Cell, vertical bool) *Cell {
+	cell := NewCell(over.Row, over.Column)
+	under := &cell
+	if vertical {
+		under.North, under.South = over.North, over.South
+		over.North.South, over.South.North = under, under
+	} else {
+		under.West, under.East = over.West, over.East
+		over.West.East, over.East.West = under, under
+	}
+	g.under = append(g.under, under)
+	return under
+}
+
+// Clone returns an independent copy of the weave grid, including the passages
+// running beneath it.  Changing the links of either grid does not affect the
+// other
+func (g *WeaveGrid) Clone() *WeaveGrid {
+	clone := &WeaveGrid{Grid: *g.Grid.Clone()}
+	for _, under := range g.under {
+		copied := clone.addUnder(clone.At(under.Row, under.Column), under.North != nil)
+		for _, n := range under.Links() {
+			copied.Link(clone.At(n.Row, n.Column))
+		}
+	}
+	return clone
+}
+
+// weaveJSON is the serialized form of a weave grid
+type weaveJSON struct {
+	gridJSON
+	// The cells which passages run beneath.  The links of the grid don't
+	// include those leading into the passages, which always join the cells on
+	// either side
+	Tunnels []tunnelJSON `json:"tunnels,omitempty"`
+}
+
+// tunnelJSON is the serialized form of a passage running beneath a cell
+type tunnelJSON struct {
+	Row    int64 `json:"row"`
+	Column int64 `json:"column"`
+	// Whether the passage runs north to south rather than east to west
+	Vertical bool `json:"vertical"`
+}
+
+// MarshalJSON records the grid in the same form as Grid.MarshalJSON, along with
+// the passages running beneath it
+func (g *WeaveGrid) MarshalJSON() ([]byte, error) {
+	out := weaveJSON{gridJSON: g.toJSON(g.isOverCell)}
+	for _, under := range g.under {
+		out.Tunnels = append(out.Tunnels, tunnelJSON{under.Row, under.Column, under.North != nil})
+	}
+	return json.Marshal(out)
+}
+
+// LoadWeaveGridJSON reconstructs a weave grid from the JSON produced by
+// WeaveGrid.MarshalJSON, including the passages running beneath it
+func LoadWeaveGridJSON(r io.Reader) (*WeaveGrid, error) {
+	var in weaveJSON
+	if err := json.NewDecoder(r).Decode(&in); err != nil {
+		return nil, fmt.Errorf("decoding grid: %v", err)
+	}
+	grid, err := in.newGrid()
+	if err != nil {
+		return nil, err
+	}
+	g := &WeaveGrid{Grid: *grid}
+	for _, t := range in.Tunnels {
+		over := g.At(t.Row, t.Column)
+		if over == nil {
+			return nil, fmt.Errorf("tunnel beneath [%d, %d] is outside the grid", t.Row, t.Column)
+		}
+		from, to := over.West, over.East
+		if t.Vertical {
+			from, to = over.North, over.South
+		}
+		if from == nil || to == nil {
+			return nil, fmt.Errorf("tunnel beneath [%d, %d] leads outside the grid", t.Row, t.Column)
+		}
+		if err := g.Tunnel(from, to); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+// WeaveBacktracker uses the recursive backtracker maze creation algorithm to
+// create a weave maze.  Besides its neighbors, each cell may carve a passage to
+// the cell beyond a neighboring corridor by tunneling beneath it
+func WeaveBacktracker(g *WeaveGrid) {
+	WeaveBacktrackerRand(g, defaultRand)
+}
+
+// WeaveBacktrackerRand is WeaveBacktracker using the provided random source
+func WeaveBacktrackerRand(g *WeaveGrid, r *rand.Rand) {
+	if g.Size() == 0 {
+		return
+	}
+	stack := []*Cell{g.RandomCellRand(r)}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		unvisited := []*Cell{}
+		for _, n := range g.weaveNeighbors(current) {
+			if !n.hasLinks() {
+				unvisited = append(unvisited, n)
+			}
+		}
+		if len(unvisited) == 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		next := unvisited[r.Intn(len(unvisited))]
+		if containsCell(current.Neighbors(), next) {
+			current.Link(next)
+		} else {
+			// weaveNeighbors only offers tunnels which are possible
+			_ = g.Tunnel(current, next)
+		}
+		stack = append(stack, next)
+	}
+}
+
+// Glyphs used by ToString to mark the cells which passages run beneath,
+// showing the direction of the passage which crosses over the tunnel
+const (
+	bridgeEastWestGlyph   = '═'
+	bridgeNorthSouthGlyph = '║'
+)
+
+// ToString creates a textual representation of the weave maze.  The walls where
+// a passage tunnels beneath a cell are left open, and the cell above the tunnel
+// is marked with ═ or ║ to show the direction of the passage crossing over it
+func (g *WeaveGrid) ToString() string {
+	bridges := map[*Cell]rune{}
+	for _, under := range g.under {
+		glyph := bridgeEastWestGlyph
+		if under.West != nil {
+			glyph = bridgeNorthSouthGlyph
+		}
+		bridges[g.At(under.Row, under.Column)] = glyph
+	}
+	return g.toString(LightWalls, 3, 1, func(cell *Cell) rune {
+		if glyph, ok := bridges[cell]; ok {
+			return glyph
+		}
+		return ' '
+	})
+}
+
+// ToPNG renders the weave maze as a PNG image.  Passages are drawn inset pixels
+// narrower than the cells on each side so that the passages running beneath the
+// grid are visible.  The image is (Columns * cellSize + 1) pixels wide and
+// (Rows * cellSize + 1) pixels tall
+func (g *WeaveGrid) ToPNG(w io.Writer, cellSize, inset int) error {
+	if cellSize < 1 {
+		return fmt.Errorf("invalid PNG cell size: %d", cellSize)
+	}
+	if inset < 1 || 2*inset >= cellSize {
+		return fmt.Errorf("invalid inset %d for a cell size of %d", inset, cellSize)
+	}
+
+	width := int(g.Columns)*cellSize + 1
+	height := int(g.Rows)*cellSize + 1
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: background}, image.Point{}, draw.Src)
+
+	line := func(x0, y0, x1, y1 int) {
+		drawLine(img, x0, y0, x1, y1, wallColor)
+	}
+	for _, cell := range g.Cells() {
+		// The edges of the cell and of the passage through its middle
+		x1, y1 := int(cell.Column)*cellSize, int(cell.Row)*cellSize
+		x2, y2 := x1+inset, y1+inset
+		x3, y3 := x1+cellSize-inset, y1+cellSize-inset
+		x4, y4 := x1+cellSize, y1+cellSize
+
+		if cell.Linked(cell.North) {
+			line(x2, y1, x2, y2)
+			line(x3, y1, x3, y2)
+		} else {
+			line(x2, y2, x3, y2)
+		}
+		if cell.Linked(cell.South) {
+			line(x2, y3, x2, y4)
+			line(x3, y3, x3, y4)
+		} else {
+			line(x2, y3, x3, y3)
+		}
+		if cell.Linked(cell.West) {
+			line(x1, y2, x2, y2)
+			line(x1, y3, x2, y3)
+		} else {
+			line(x2, y2, x2, y3)
+		}
+		if cell.Linked(cell.East) {
+			line(x3, y2, x4, y2)
+			line(x3, y3, x4, y3)
+		} else {
+			line(x3, y2, x3, y3)
+		}
+	}
+
+	// Passages beneath the grid are only visible where they enter and leave
+	// the cell above them
+	for _, cell := range g.under {
+		x1, y1 := int(cell.Column)*cellSize, int(cell.Row)*cellSize
+		x2, y2 := x1+inset, y1+inset
+		x3, y3 := x1+cellSize-inset, y1+cellSize-inset
+		x4, y4 := x1+cellSize, y1+cellSize
+
+		if cell.North != nil {
+			line(x2, y1, x2, y2)
+			line(x3, y1, x3, y2)
+			line(x2, y3, x2, y4)
+			line(x3, y3, x3, y4)
+		} else {
+			line(x1, y2, x2, y2)
+			line(x1, y3, x2, y3)
+			line(x3, y2, x4, y2)
+			line(x3, y3, x4, y3)
+		}
+	}
+
+	return png.Encode(w, img)
+}
@@ -0,0 +1,222 @@
+package maze
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// crossing returns a 3x3 weave grid in which a corridor runs across the middle
+// row and a tunnel runs beneath it down the middle column
+func crossing(t *testing.T) *WeaveGrid {
+	t.Helper()
+	w := NewWeaveGrid(3, 3)
+	w.At(1, 0).Link(w.At(1, 1))
+	w.At(1, 1).Link(w.At(1, 2))
+	if err := w.Tunnel(w.At(0, 1), w.At(2, 1)); err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func TestWeaveToString(t *testing.T) {
+	eastWest := crossing(t)
+	northSouth := NewWeaveGrid(3, 3)
+	northSouth.At(0, 1).Link(northSouth.At(1, 1))
+	northSouth.At(1, 1).Link(northSouth.At(2, 1))
+	if err := northSouth.Tunnel(northSouth.At(1, 0), northSouth.At(1, 2)); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		grid *WeaveGrid
+		want string
+	}{
+		{"Empty", NewWeaveGrid(1, 2), "┌───┬───┐   \n│   │   │   \n└───┴───┘   \n"},
+		{"TunnelNorthSouth", eastWest, "┌───┬───┬───┐   \n│   │   │   │   \n├───┘   └───┤   \n│     ═     │   \n├───┐   ┌───┤   \n│   │   │   │   \n└───┴───┴───┘   \n"},
+		{"TunnelEastWest", northSouth, "┌───┬───┬───┐   \n│   │   │   │   \n├───┘   └───┤   \n│     ║     │   \n├───┐   ┌───┤   \n│   │   │   │   \n└───┴───┴───┘   \n"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.grid.ToString(); got != tc.want {
+				t.Errorf("ToString() =\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWeaveIsPerfect(t *testing.T) {
+	tunnels := 0
+	for seed := int64(0); seed < 10; seed++ {
+		w := NewWeaveGrid(10, 10)
+		WeaveBacktrackerRand(w, rand.New(rand.NewSource(seed)))
+		tunnels += len(w.UnderCells())
+		if !IsPerfect(&w.Grid) {
+			t.Errorf("seed %d: maze is not perfect:\n%s", seed, w.ToString())
+		}
+		stats := Analyze(&w.Grid)
+		if total := stats.DeadEnds + stats.Corridors + stats.TJunctions + stats.Crossroads; int64(total) != w.Size()+int64(len(w.UnderCells())) {
+			t.Errorf("seed %d: Analyze() counted %d cells, want %d", seed, total, w.Size()+int64(len(w.UnderCells())))
+		}
+	}
+	if tunnels == 0 {
+		t.Error("no maze tunneled beneath a cell")
+	}
+
+	// The crossing on its own leaves the corners unreachable.  Joining the top
+	// and bottom rows to the tunnel and the left end of the corridor to the top
+	// row makes a tree, and joining the corridor to the bottom row too closes a
+	// loop through the tunnel
+	w := crossing(t)
+	if IsPerfect(&w.Grid) {
+		t.Error("IsPerfect() of a maze with unreachable corners = true")
+	}
+	for _, pair := range [][4]int64{{0, 0, 0, 1}, {0, 1, 0, 2}, {2, 0, 2, 1}, {2, 1, 2, 2}, {0, 0, 1, 0}} {
+		w.At(pair[0], pair[1]).Link(w.At(pair[2], pair[3]))
+	}
+	if !IsPerfect(&w.Grid) {
+		t.Errorf("IsPerfect() of a woven tree = false:\n%s", w.ToString())
+	}
+	w.At(1, 0).Link(w.At(2, 0))
+	if IsPerfect(&w.Grid) {
+		t.Error("IsPerfect() of a woven maze with a loop = true")
+	}
+}
+
+func TestWeaveAnalyze(t *testing.T) {
+	stats := Analyze(&crossing(t).Grid)
+	// The four ends of the crossing are dead ends, and the corridor over the
+	// tunnel and the tunnel itself are both straight
+	want := Stats{DeadEnds: 4, Corridors: 2, River: 0.2}
+	if stats != want {
+		t.Errorf("Analyze() = %+v, want %+v", stats, want)
+	}
+}
+
+func TestTunnelErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to [2]int64
+	}{
+		{"NotInLine", [2]int64{0, 0}, [2]int64{1, 1}},
+		{"Adjacent", [2]int64{0, 0}, [2]int64{0, 1}},
+		{"NoCorridor", [2]int64{1, 0}, [2]int64{1, 2}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := NewWeaveGrid(3, 3)
+			if err := w.Tunnel(w.At(tc.from[0], tc.from[1]), w.At(tc.to[0], tc.to[1])); err == nil {
+				t.Error("Tunnel() returned no error")
+			}
+			if len(w.UnderCells()) != 0 {
+				t.Errorf("Tunnel() left %d cells beneath the grid", len(w.UnderCells()))
+			}
+		})
+	}
+}
+
+// underPositions returns the position of each passage beneath a weave grid and
+// whether it runs north to south
+func underPositions(g *WeaveGrid) [][3]int64 {
+	ret := [][3]int64{}
+	for _, under := range g.UnderCells() {
+		vertical := int64(0)
+		if under.North != nil {
+			vertical = 1
+		}
+		ret = append(ret, [3]int64{under.Row, under.Column, vertical})
+	}
+	return ret
+}
+
+func TestWeaveClone(t *testing.T) {
+	for seed := int64(0); seed < 5; seed++ {
+		w := NewWeaveGrid(8, 8)
+		WeaveBacktrackerRand(w, rand.New(rand.NewSource(seed)))
+		clone := w.Clone()
+		if clone.ToString() != w.ToString() || !reflect.DeepEqual(underPositions(clone), underPositions(w)) {
+			t.Fatalf("seed %d: Clone() =\n%s\nwant:\n%s", seed, clone.ToString(), w.ToString())
+		}
+		if !IsPerfect(&clone.Grid) {
+			t.Errorf("seed %d: cloned maze is not perfect:\n%s", seed, clone.ToString())
+		}
+		for _, cell := range clone.Cells() {
+			for _, n := range append(cell.Neighbors(), cell.Links()...) {
+				if n != clone.At(n.Row, n.Column) && !containsCell(clone.UnderCells(), n) {
+					t.Fatalf("seed %d: cloned cell [%d, %d] leads to a cell of the original grid", seed, cell.Row, cell.Column)
+				}
+			}
+		}
+
+		before, tunnels := w.ToString(), underPositions(w)
+		clone.Reset()
+		if w.ToString() != before || !reflect.DeepEqual(underPositions(w), tunnels) {
+			t.Errorf("seed %d: resetting the clone changed the original", seed)
+		}
+	}
+}
+
+func TestWeaveJSONRoundTrip(t *testing.T) {
+	for seed := int64(0); seed < 5; seed++ {
+		w := NewWeaveGrid(8, 8)
+		WeaveBacktrackerRand(w, rand.New(rand.NewSource(seed)))
+		data, err := json.Marshal(w)
+		if err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := LoadWeaveGridJSON(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("seed %d: LoadWeaveGridJSON: %v\n%s", seed, err, data)
+		}
+		if loaded.ToString() != w.ToString() || !reflect.DeepEqual(underPositions(loaded), underPositions(w)) {
+			t.Errorf("seed %d: loaded grid differs:\n%s\nwant:\n%s", seed, loaded.ToString(), w.ToString())
+		}
+		if !IsPerfect(&loaded.Grid) {
+			t.Errorf("seed %d: loaded maze is not perfect:\n%s", seed, loaded.ToString())
+		}
+		again, err := json.Marshal(loaded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, again) {
+			t.Errorf("seed %d: JSON changed after a round trip:\n%s\nwant:\n%s", seed, again, data)
+		}
+	}
+}
+
+func TestWeaveMarshalJSON(t *testing.T) {
+	data, err := json.Marshal(crossing(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"rows":3,"columns":3,"links":[[[],[],[]],[["E"],["E","W"],["W"]],[[],[],[]]],` +
+		`"tunnels":[{"row":1,"column":1,"vertical":true}]}`
+	if string(data) != want {
+		t.Errorf("MarshalJSON() = %s, want %s", data, want)
+	}
+}
+
+func TestLoadWeaveGridJSONErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+	}{
+		{"Malformed", `{"rows":`},
+		{"BadGrid", `{"rows":1,"columns":2,"links":[[[]]]}`},
+		{"OutsideGrid", `{"rows":1,"columns":1,"links":[[[]]],"tunnels":[{"row":3,"column":0,"vertical":true}]}`},
+		{"LeavesGrid", `{"rows":3,"columns":3,"links":[[[],[],[]],[["E"],["E","W"],["W"]],[[],[],[]]],"tunnels":[{"row":0,"column":1,"vertical":true}]}`},
+		// The cell above a tunnel must be a corridor running across it
+		{"NotCorridor", `{"rows":3,"columns":3,"links":[[[],[],[]],[[],[],[]],[[],[],[]]],"tunnels":[{"row":1,"column":1,"vertical":true}]}`},
+		{"SameDirection", `{"rows":3,"columns":3,"links":[[[],[],[]],[["E"],["E","W"],["W"]],[[],[],[]]],"tunnels":[{"row":1,"column":1,"vertical":false}]}`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := LoadWeaveGridJSON(strings.NewReader(tc.json)); err == nil {
+				t.Errorf("LoadWeaveGridJSON(%s) returned no error", tc.json)
+			}
+		})
+	}
+}
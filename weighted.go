@@ -0,0 +1,71 @@
+package maze
+
+import (
+	"container/heap"
+	"fmt"
+)
+
+// WeightedDistances measures the cost of reaching every cell root can reach by
+// following links, where entering a cell costs the weight of that cell, such as
+// to make mud or water slower to cross.  It uses Dijkstra's algorithm.  An error
+// is returned if a reachable cell has a negative weight
+func WeightedDistances(root *Cell, weight func(*Cell) int64) (*Distances, error) {
+	d, _, err := weightedSearch(root, nil, weight)
+	return d, err
+}
+
+// WeightedShortestPath returns the cells along the cheapest route through linked
+// passages from one cell to another, including both ends, where entering a cell
+// costs the weight of that cell.  If the destination can't be reached, it
+// returns nil and false.  An error is returned if a cell the search reaches has
+// a negative weight
+func WeightedShortestPath(from, to *Cell, weight func(*Cell) int64) ([]*Cell, bool, error) {
+	d, prev, err := weightedSearch(from, to, weight)
+	if err != nil {
+		return nil, false, err
+	}
+	if _, ok := d.Get(to); !ok {
+		return nil, false, nil
+	}
+	return tracePath(prev, to), true, nil
+}
+
+// weightedSearch runs Dijkstra's algorithm outward from root, stopping early
+// once goal is reached if goal is not nil.  It returns the costs of the cells
+// found along with the predecessor of each on its cheapest route
+func weightedSearch(root, goal *Cell, weight func(*Cell) int64) (*Distances, map[*Cell]*Cell, error) {
+	d := &Distances{
+		root:  root,
+		cells: map[*Cell]int64{},
+		order: []*Cell{}}
+	prev := map[*Cell]*Cell{root: nil}
+	best := map[*Cell]int64{root: 0}
+	queue := &aStarQueue{{cell: root}}
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(aStarItem)
+		cell := item.cell
+		if _, done := d.cells[cell]; done || item.cost > best[cell] {
+			continue
+		}
+		d.cells[cell] = item.cost
+		d.order = append(d.order, cell)
+		if cell == goal {
+			break
+		}
+
+		for _, n := range cell.Links() {
+			w := weight(n)
+			if w < 0 {
+				return nil, nil, fmt.Errorf("cell [%d, %d] has negative weight %d", n.Row, n.Column, w)
+			}
+			cost := item.cost + w
+			if b, ok := best[n]; !ok || cost < b {
+				best[n] = cost
+				prev[n] = cell
+				// Without a heuristic the estimate is just the cost so far
+				heap.Push(queue, aStarItem{cell: n, cost: cost, estimate: cost})
+			}
+		}
+	}
+	return d, prev, nil
+}
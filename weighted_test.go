@@ -0,0 +1,114 @@
+package maze
+
+import (
+	"reflect"
+	"testing"
+)
+
+// mud makes the middle of the second row expensive to cross
+func mud(c *Cell) int64 {
+	if c.Row == 1 && c.Column > 0 && c.Column < 4 {
+		return 50
+	}
+	return 1
+}
+
+func TestWeightedDistances(t *testing.T) {
+	open := NewFullyLinkedGrid(3, 5)
+	tests := []struct {
+		name   string
+		grid   *Grid
+		root   [2]int64
+		weight func(*Cell) int64
+		want   [][]int64
+		max    int64
+	}{
+		{"Uniform", serpentine(2, 3), [2]int64{0, 0}, func(*Cell) int64 { return 1 }, [][]int64{{0, 1, 2}, {5, 4, 3}}, 5},
+		{"Free", serpentine(2, 3), [2]int64{0, 0}, func(*Cell) int64 { return 0 }, [][]int64{{0, 0, 0}, {0, 0, 0}}, 0},
+		{"Mud", &open, [2]int64{1, 0}, mud, [][]int64{{1, 2, 3, 4, 5}, {0, 50, 53, 54, 6}, {1, 2, 3, 4, 5}}, 54},
+		{"Unreachable", linkedGrid(1, 3, [][4]int64{{0, 0, 0, 1}}), [2]int64{0, 0}, func(*Cell) int64 { return 2 }, [][]int64{{0, 2, -1}}, 2},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := WeightedDistances(tc.grid.At(tc.root[0], tc.root[1]), tc.weight)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for r, row := range tc.want {
+				for c, want := range row {
+					got, ok := d.Get(tc.grid.At(int64(r), int64(c)))
+					if ok != (want >= 0) || (ok && got != want) {
+						t.Errorf("Get([%d, %d]) = %d, %v, want %d", r, c, got, ok, want)
+					}
+				}
+			}
+			if _, max := d.Max(); max != tc.max {
+				t.Errorf("Max() = %d, want %d", max, tc.max)
+			}
+		})
+	}
+}
+
+func TestWeightedShortestPath(t *testing.T) {
+	open := NewFullyLinkedGrid(3, 5)
+	// The route around the mud is cheaper along the first row than the last
+	mudAndSand := func(c *Cell) int64 {
+		if c.Row == 2 {
+			return 2
+		}
+		return mud(c)
+	}
+	tests := []struct {
+		name     string
+		grid     *Grid
+		from, to [2]int64
+		weight   func(*Cell) int64
+		want     [][2]int64
+		found    bool
+	}{
+		{"SameCell", &open, [2]int64{1, 1}, [2]int64{1, 1}, mud, [][2]int64{{1, 1}}, true},
+		{"Serpentine", serpentine(2, 2), [2]int64{0, 0}, [2]int64{1, 0}, func(*Cell) int64 { return 3 }, [][2]int64{{0, 0}, {0, 1}, {1, 1}, {1, 0}}, true},
+		{"AroundMud", &open, [2]int64{1, 0}, [2]int64{1, 4}, mudAndSand, [][2]int64{{1, 0}, {0, 0}, {0, 1}, {0, 2}, {0, 3}, {0, 4}, {1, 4}}, true},
+		{"Unreachable", linkedGrid(1, 3, [][4]int64{{0, 0, 0, 1}}), [2]int64{0, 0}, [2]int64{0, 2}, mud, nil, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path, ok, err := WeightedShortestPath(tc.grid.At(tc.from[0], tc.from[1]), tc.grid.At(tc.to[0], tc.to[1]), tc.weight)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != tc.found {
+				t.Fatalf("WeightedShortestPath() found = %v, want %v", ok, tc.found)
+			}
+			if !ok {
+				if path != nil {
+					t.Errorf("WeightedShortestPath() = %v, want nil", positions(path))
+				}
+				return
+			}
+			if got := positions(path); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("WeightedShortestPath() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWeightedNegative(t *testing.T) {
+	g := serpentine(2, 2)
+	negative := func(c *Cell) int64 {
+		if c.Row == 1 {
+			return -1
+		}
+		return 1
+	}
+	if _, err := WeightedDistances(g.At(0, 0), negative); err == nil {
+		t.Error("WeightedDistances() with a negative weight returned no error")
+	}
+	if _, _, err := WeightedShortestPath(g.At(0, 0), g.At(1, 0), negative); err == nil {
+		t.Error("WeightedShortestPath() with a negative weight returned no error")
+	}
+	// The search stops before it reaches the negative cells
+	if _, ok, err := WeightedShortestPath(g.At(0, 0), g.At(0, 0), negative); err != nil || !ok {
+		t.Errorf("WeightedShortestPath() to the start = %v, %v, want no error", ok, err)
+	}
+}
@@ -0,0 +1,97 @@
+package maze
+
+import (
+	"context"
+	"math/rand"
+)
+
+// Wilsons uses Wilson's maze creation algorithm to create a maze in a
+// rectangular grid.  Starting from a single visited cell, it repeatedly takes a
+// random walk from an unvisited cell until the walk reaches a visited cell,
+// erasing any loops the walk makes along the way, and then carves the walk into
+// the maze.  Like Aldous-Broder it produces every possible maze with equal
+// probability, but it finishes faster on large grids.  If a mask splits the
+// grid into separate regions, only the region containing the randomly chosen
+// starting cell is carved
+func Wilsons(g *Grid) {
+	WilsonsRand(g, defaultRand)
+}
+
+// WilsonsRand is Wilsons using the provided random source
+func WilsonsRand(g *Grid, r *rand.Rand) {
+	_ = wilsons(context.Background(), g, r)
+}
+
+// WilsonsCtx is Wilsons, but stops early and returns the context's error if ctx
+// is cancelled.  The passages carved before then are left in place
+func WilsonsCtx(ctx context.Context, g *Grid) error {
+	return wilsons(ctx, g, defaultRand)
+}
+
+// wilsons implements Wilsons using the provided random source, stopping early
+// if ctx is cancelled
+func wilsons(ctx context.Context, g *Grid, r *rand.Rand) error {
+	if g.Size() == 0 {
+		return nil
+	}
+
+	// Walks can never leave the region the maze starts in, so only the cells of
+	// that region are waiting to be visited.  Keep them in a list which supports
+	// removal from the middle
+	start := g.RandomCellRand(r)
+	reached := region(start)
+	unvisited := []*Cell{}
+	position := map[*Cell]int{}
+	for _, cell := range g.Cells() {
+		if reached[cell] {
+			position[cell] = len(unvisited)
+			unvisited = append(unvisited, cell)
+		}
+	}
+	visit := func(cell *Cell) {
+		i := position[cell]
+		last := unvisited[len(unvisited)-1]
+		unvisited[i], position[last] = last, i
+		unvisited = unvisited[:len(unvisited)-1]
+		delete(position, cell)
+	}
+	visit(start)
+
+	steps := 0
+	for len(unvisited) > 0 {
+		// Walk until reaching the maze, remembering where each cell on the walk
+		// appears so that loops can be erased
+		cell := unvisited[r.Intn(len(unvisited))]
+		path := []*Cell{cell}
+		onPath := map[*Cell]int{cell: 0}
+		for {
+			if steps%contextCheckInterval == 0 {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+			}
+			steps++
+			neighbors := cell.Neighbors()
+			cell = neighbors[r.Intn(len(neighbors))]
+			if i, ok := onPath[cell]; ok {
+				for _, erased := range path[i+1:] {
+					delete(onPath, erased)
+				}
+				path = path[:i+1]
+			} else {
+				onPath[cell] = len(path)
+				path = append(path, cell)
+			}
+			if _, ok := position[cell]; !ok {
+				break
+			}
+		}
+
+		// The last cell of the path is already part of the maze
+		for i := 0; i < len(path)-1; i++ {
+			path[i].Link(path[i+1])
+			visit(path[i])
+		}
+	}
+	return nil
+}
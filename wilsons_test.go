@@ -0,0 +1,51 @@
+package maze
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestWilsons(t *testing.T) {
+	sizes := []struct {
+		rows, columns int64
+	}{
+		{1, 1},
+		{1, 7},
+		{7, 1},
+		{10, 10},
+		{6, 15},
+	}
+	for _, s := range sizes {
+		for seed := int64(0); seed < 5; seed++ {
+			g := NewGrid(s.rows, s.columns)
+			WilsonsRand(&g, rand.New(rand.NewSource(seed)))
+			ends := 0
+			for _, cell := range g.Cells() {
+				ends += cell.linkCount()
+			}
+			if int64(ends/2) != g.Size()-1 {
+				t.Errorf("%dx%d seed %d: carved %d passages, want %d", s.rows, s.columns, seed, ends/2, g.Size()-1)
+			}
+			if reached := int64(len(Reachable(g.At(0, 0)))); reached != g.Size() {
+				t.Errorf("%dx%d seed %d: %d of %d cells are connected:\n%s", s.rows, s.columns, seed, reached, g.Size(), g.ToString())
+			}
+		}
+	}
+}
+
+func TestWilsonsMaskedRegions(t *testing.T) {
+	// Two regions of different sizes, separated by a disabled column.  Only
+	// the region containing the starting cell is carved
+	m := maskFromString(t, "..X....\n..X....\n..X....")
+	for seed := int64(0); seed < 10; seed++ {
+		g := NewMaskedGrid(m)
+		WilsonsRand(g, rand.New(rand.NewSource(seed)))
+		left, right := len(Reachable(g.At(0, 0))), len(Reachable(g.At(0, 3)))
+		if !(left == 6 && right == 1) && !(left == 1 && right == 12) {
+			t.Errorf("seed %d: regions of %d and %d cells are connected, want one region carved:\n%s", seed, left, right, g.ToString())
+		}
+		if len(Cycles(g)) != 0 {
+			t.Errorf("seed %d: maze has loops:\n%s", seed, g.ToString())
+		}
+	}
+}